@@ -0,0 +1,194 @@
+// Package bench measures run time and memory usage of solvers and compares
+// the measurements against a stored baseline.
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"aoc/solver"
+)
+
+// Result holds the measurements of a single part.
+type Result struct {
+	Day    int           `json:"day"`
+	Part   int           `json:"part"`
+	Runs   int           `json:"runs"`
+	Min    time.Duration `json:"min_ns"`
+	Median time.Duration `json:"median_ns"`
+	Mean   time.Duration `json:"mean_ns"`
+	// Allocations and allocated bytes per run.
+	Allocs uint64 `json:"allocs_per_run"`
+	Bytes  uint64 `json:"bytes_per_run"`
+	// Peak heap usage above the heap in use before the run, sampled
+	// periodically and therefore approximate.
+	PeakBytes uint64 `json:"peak_bytes"`
+}
+
+// sampleInterval is the interval the heap is sampled for the peak usage.
+const sampleInterval = time.Millisecond
+
+// Measure solves the given part of s runs times on data. It fails unless
+// runs is at least 1.
+func Measure(s solver.Solver, day, part, runs int, data []byte) (Result, error) {
+	res := Result{Day: day, Part: part, Runs: runs}
+	if runs < 1 {
+		return res, fmt.Errorf("invalid number of runs %d", runs)
+	}
+	times := make([]time.Duration, 0, runs)
+
+	var before, after runtime.MemStats
+	for i := 0; i < runs; i++ {
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		stop := make(chan struct{})
+		peak := samplePeak(before.HeapAlloc, stop)
+
+		start := time.Now()
+		_, err := solver.Solve(s, part, bytes.NewReader(data))
+		elapsed := time.Since(start)
+
+		close(stop)
+		runtime.ReadMemStats(&after)
+		if err != nil {
+			return res, err
+		}
+
+		times = append(times, elapsed)
+		res.Allocs += after.Mallocs - before.Mallocs
+		res.Bytes += after.TotalAlloc - before.TotalAlloc
+		if p := peak(); p > res.PeakBytes {
+			res.PeakBytes = p
+		}
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	var total time.Duration
+	for _, t := range times {
+		total += t
+	}
+	res.Min = times[0]
+	res.Median = times[len(times)/2]
+	res.Mean = total / time.Duration(runs)
+	res.Allocs /= uint64(runs)
+	res.Bytes /= uint64(runs)
+
+	return res, nil
+}
+
+// samplePeak samples the heap until stop is closed. The returned function
+// waits for the sampler and reports the peak heap usage above base.
+func samplePeak(base uint64, stop chan struct{}) func() uint64 {
+	var (
+		wg   sync.WaitGroup
+		peak uint64
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(sampleInterval)
+		defer ticker.Stop()
+
+		var ms runtime.MemStats
+		for {
+			runtime.ReadMemStats(&ms)
+			if ms.HeapAlloc > base && ms.HeapAlloc-base > peak {
+				peak = ms.HeapAlloc - base
+			}
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() uint64 {
+		wg.Wait()
+		return peak
+	}
+}
+
+// ------------------------------- Persistence --------------------------------
+
+// Load reads results written by Save.
+func Load(path string) ([]Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	err = json.Unmarshal(data, &results)
+	return results, err
+}
+
+// Save writes results as JSON to path.
+func Save(path string, results []Result) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// -------------------------------- Comparison --------------------------------
+
+// Regression describes a measurement which got worse compared to the
+// baseline.
+type Regression struct {
+	Day, Part int
+	Metric    string
+	Old, New  float64
+}
+
+// Ratio is the relative change of the metric, e.g. 0.5 for 50% worse.
+func (r Regression) Ratio() float64 {
+	return r.New/r.Old - 1
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("day %d part %d: %s %+.1f%% (%.0f -> %.0f)",
+		r.Day, r.Part, r.Metric, 100*r.Ratio(), r.Old, r.New)
+}
+
+// Compare reports the median time and allocated bytes of results which are
+// worse than in baseline by more than threshold, e.g. 0.2 for 20%. Parts
+// missing in the baseline are ignored.
+func Compare(baseline, results []Result, threshold float64) []Regression {
+	old := make(map[[2]int]Result)
+	for _, r := range baseline {
+		old[[2]int{r.Day, r.Part}] = r
+	}
+
+	var regressions []Regression
+	for _, r := range results {
+		b, ok := old[[2]int{r.Day, r.Part}]
+		if !ok {
+			continue
+		}
+
+		metrics := []struct {
+			name     string
+			old, new float64
+		}{
+			{"time", float64(b.Median), float64(r.Median)},
+			{"bytes", float64(b.Bytes), float64(r.Bytes)},
+		}
+		for _, m := range metrics {
+			if m.old > 0 && m.new/m.old-1 > threshold {
+				regressions = append(regressions, Regression{r.Day, r.Part, m.name, m.old, m.new})
+			}
+		}
+	}
+	return regressions
+}
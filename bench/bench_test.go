@@ -0,0 +1,66 @@
+package bench
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"aoc/solver"
+)
+
+type sleeper struct{}
+
+func (sleeper) Part1(r io.Reader) (solver.Answer, error) {
+	time.Sleep(2 * time.Millisecond)
+	_ = make([]byte, 1<<20)
+	return "", nil
+}
+
+func (sleeper) Part2(r io.Reader) (solver.Answer, error) {
+	return "", errors.New("unsolved")
+}
+
+func TestMeasure(t *testing.T) {
+	res, err := Measure(sleeper{}, 1, 1, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Runs != 3 || res.Min < 2*time.Millisecond || res.Min > res.Median {
+		t.Errorf("unexpected timings %+v", res)
+	}
+	if res.Bytes < 1<<20 {
+		t.Errorf("Bytes = %d, want at least %d", res.Bytes, 1<<20)
+	}
+
+	if _, err := Measure(sleeper{}, 1, 2, 1, nil); err == nil {
+		t.Error("Measure did not report the error of the solver")
+	}
+	for _, runs := range []int{0, -1} {
+		if _, err := Measure(sleeper{}, 1, 1, runs, nil); err == nil {
+			t.Errorf("Measure with %d runs didn't fail", runs)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := []Result{
+		{Day: 1, Part: 1, Median: 100, Bytes: 1000},
+		{Day: 1, Part: 2, Median: 100, Bytes: 1000},
+	}
+	results := []Result{
+		{Day: 1, Part: 1, Median: 110, Bytes: 1000},
+		{Day: 1, Part: 2, Median: 200, Bytes: 1500},
+		{Day: 2, Part: 1, Median: 999, Bytes: 999},
+	}
+
+	regressions := Compare(baseline, results, 0.2)
+	if len(regressions) != 2 {
+		t.Fatalf("Compare = %v, want time and bytes of day 1 part 2", regressions)
+	}
+	for _, r := range regressions {
+		if r.Day != 1 || r.Part != 2 {
+			t.Errorf("unexpected regression %v", r)
+		}
+	}
+}
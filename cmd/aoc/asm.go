@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"aoc/day10"
+)
+
+// runAsm converts a day 10 program from text to its binary form if
+// assemble is set, and back otherwise.
+func runAsm(name string, assemble bool, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	out := fs.String("o", "-", "output file, \"-\" for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("usage: aoc %s [-o <file>] [<program>]", name)
+	}
+
+	path := "-"
+	if fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+	data, err := readInput(path)
+	if err != nil {
+		return err
+	}
+
+	var result bytes.Buffer
+	if assemble {
+		bin, err := day10.Assemble(bytes.NewReader(data))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, diagnose(displayPath(path), data, err))
+			return fmt.Errorf("%s failed", name)
+		}
+		result.Write(bin)
+	} else {
+		program, err := day10.LoadProgram(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", displayPath(path), err)
+		}
+		if err := day10.Disassemble(&result, program); err != nil {
+			return err
+		}
+	}
+
+	if *out == "-" {
+		_, err = io.Copy(os.Stdout, &result)
+		return err
+	}
+	return os.WriteFile(*out, result.Bytes(), 0o644)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"aoc/bench"
+	"aoc/solver"
+)
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	day := fs.Int("day", 0, "day to benchmark (default all days)")
+	part := fs.Int("part", 0, "part to benchmark (1 or 2), both if omitted")
+	runs := fs.Int("n", 3, "number of runs per part")
+	dir := fs.String("dir", ".", "directory containing the dayNN directories")
+	out := fs.String("out", "", "write the results as JSON to this file")
+	baseline := fs.String("baseline", "", "compare the results against this JSON file")
+	threshold := fs.Float64("threshold", 0.2, "relative slowdown reported as regression")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *runs < 1 {
+		fs.Usage()
+		return fmt.Errorf("-n must be at least 1, got %d", *runs)
+	}
+
+	days := solver.Days()
+	if *day != 0 {
+		if _, ok := solver.Lookup(*day); !ok {
+			return fmt.Errorf("no solver registered for day %d", *day)
+		}
+		days = []int{*day}
+	}
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+
+	// Rows are printed as soon as a part is measured, hence the fixed
+	// column widths.
+	const row = "%3v %4v %12v %12v %12v %11v %11v %11v\n"
+	fmt.Printf(row, "Day", "Part", "Min", "Median", "Mean", "Allocs/run", "Bytes/run", "Peak heap")
+
+	var results []bench.Result
+	for _, d := range days {
+		s, _ := solver.Lookup(d)
+		data, err := readInput(defaultInput(*dir, d))
+		if err != nil {
+			return err
+		}
+
+		for _, p := range parts {
+			res, err := bench.Measure(s, d, p, *runs, data)
+			if err != nil {
+				return fmt.Errorf("day %d part %d: %w", d, p, err)
+			}
+			results = append(results, res)
+
+			fmt.Printf(row, d, p, round(res.Min), round(res.Median), round(res.Mean),
+				res.Allocs, byteSize(res.Bytes), byteSize(res.PeakBytes))
+		}
+	}
+
+	if *out != "" {
+		if err := bench.Save(*out, results); err != nil {
+			return err
+		}
+	}
+
+	if *baseline == "" {
+		return nil
+	}
+
+	old, err := bench.Load(*baseline)
+	if err != nil {
+		return err
+	}
+	regressions := bench.Compare(old, results, *threshold)
+	if len(regressions) == 0 {
+		fmt.Printf("\nno regressions above %.0f%% compared to %s\n", 100**threshold, *baseline)
+		return nil
+	}
+
+	fmt.Printf("\nregressions above %.0f%% compared to %s:\n", 100**threshold, *baseline)
+	for _, r := range regressions {
+		fmt.Println("  " + r.String())
+	}
+	return fmt.Errorf("%d regressions", len(regressions))
+}
+
+func round(d time.Duration) time.Duration {
+	switch {
+	case d > time.Second:
+		return d.Round(time.Millisecond)
+	case d > time.Millisecond:
+		return d.Round(time.Microsecond)
+	default:
+		return d
+	}
+}
+
+func byteSize(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"aoc/input"
+)
+
+// position formats err as "path:line:column: message" if it is a parse
+// error and as "path: message" otherwise.
+func position(path string, err error) string {
+	var pe *input.ParseError
+	switch {
+	case !errors.As(err, &pe):
+		return fmt.Sprintf("%s: %v", path, err)
+	case pe.Line == 0:
+		return fmt.Sprintf("%s: %s", path, pe.Message())
+	case pe.Column == 0:
+		return fmt.Sprintf("%s:%d: %s", path, pe.Line, pe.Message())
+	default:
+		return fmt.Sprintf("%s:%d:%d: %s", path, pe.Line, pe.Column, pe.Message())
+	}
+}
+
+// diagnose formats err like a compiler diagnostic. Parse errors quote the
+// offending line of data and mark the offending text:
+//
+//	day07/input.txt:12:10: invalid integer: "abc"
+//	   12 | 14848514 abc
+//	      |          ^^^
+func diagnose(path string, data []byte, err error) string {
+	msg := position(path, err)
+
+	var pe *input.ParseError
+	if !errors.As(err, &pe) || pe.Line == 0 {
+		return msg
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	if pe.Line > len(lines) {
+		return msg
+	}
+	line := strings.TrimRight(string(lines[pe.Line-1]), "\r")
+
+	var sb strings.Builder
+	sb.WriteString(msg)
+	gutter := fmt.Sprintf("%5d", pe.Line)
+	fmt.Fprintf(&sb, "\n%s | %s", gutter, line)
+
+	if pe.Column > 0 && pe.Column <= len(line)+1 {
+		width := utf8.RuneCountInString(pe.Text)
+		if width == 0 {
+			width = 1
+		}
+		indent := utf8.RuneCountInString(line[:pe.Column-1])
+		fmt.Fprintf(&sb, "\n%s | %s%s", strings.Repeat(" ", len(gutter)),
+			strings.Repeat(" ", indent), strings.Repeat("^", width))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,51 @@
+// Command aoc runs the registered puzzle solutions.
+//
+// Usage:
+//
+//	aoc run -day 7 -part 2 [-input path]
+//	aoc run -all [-dir path]
+//	aoc bench [-day 7] [-n 3] [-out bench.json] [-baseline bench.json]
+//	aoc asm [-o program.bin] [program.txt]
+//	aoc disasm [-o program.txt] [program.bin]
+package main
+
+import (
+	"fmt"
+	"os"
+
+	_ "aoc/days"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: aoc run -day <N> [-part <1|2>] [-input <path>] [day flags]")
+	fmt.Fprintln(os.Stderr, "       aoc run -all [-dir <path>]")
+	fmt.Fprintln(os.Stderr, "       aoc bench [-day <N>] [-part <1|2>] [-n <runs>] [-out <file>] [-baseline <file>] [-threshold <ratio>]")
+	fmt.Fprintln(os.Stderr, "       aoc asm [-o <file>] [<program>]      day 10 program to binary")
+	fmt.Fprintln(os.Stderr, "       aoc disasm [-o <file>] [<program>]   day 10 program to text")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = run(os.Args[2:])
+	case "bench":
+		err = runBench(os.Args[2:])
+	case "asm":
+		err = runAsm("asm", true, os.Args[2:])
+	case "disasm":
+		err = runAsm("disasm", false, os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "aoc:", err)
+		os.Exit(1)
+	}
+}
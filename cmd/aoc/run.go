@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"aoc/solver"
+)
+
+// result is the outcome of running a single part.
+type result struct {
+	answer  solver.Answer
+	err     error
+	elapsed time.Duration
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	day := fs.Int("day", 0, "day to run")
+	part := fs.Int("part", 0, "part to run (1 or 2), both if omitted")
+	inputPath := fs.String("input", "", "puzzle input, \"-\" for stdin (default <dir>/dayNN/input.txt)")
+	all := fs.Bool("all", false, "run every registered day against its input.txt")
+	dir := fs.String("dir", ".", "directory containing the dayNN directories")
+
+	// Days may bring their own flags, so the day is looked up before the
+	// arguments are parsed.
+	if d, ok := dayFromArgs(args); ok {
+		if s, ok := solver.Lookup(d); ok {
+			if c, ok := s.(solver.Configurable); ok {
+				c.Flags(fs)
+			}
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *all {
+		return runAll(*dir)
+	}
+
+	s, ok := solver.Lookup(*day)
+	if !ok {
+		return fmt.Errorf("no solver registered for day %d", *day)
+	}
+
+	path := *inputPath
+	if path == "" {
+		path = defaultInput(*dir, *day)
+	}
+	data, err := readInput(path)
+	if err != nil {
+		return err
+	}
+
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+
+	for _, p := range parts {
+		res := solve(s, p, data)
+		if res.err != nil {
+			fmt.Fprintln(os.Stderr, diagnose(displayPath(path), data, res.err))
+			return fmt.Errorf("day %d part %d failed", *day, p)
+		}
+		printAnswer(p, res.answer)
+	}
+
+	return nil
+}
+
+func runAll(dir string) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "Day\tPart 1\tPart 2\tTime\t")
+
+	var (
+		failed    bool
+		multiline []string
+	)
+
+	for _, day := range solver.Days() {
+		s, _ := solver.Lookup(day)
+		path := defaultInput(dir, day)
+		data, err := readInput(path)
+		if err != nil {
+			fmt.Fprintf(tw, "%d\terror: %v\t\t\t\n", day, err)
+			failed = true
+			continue
+		}
+
+		var (
+			cells [2]string
+			total time.Duration
+		)
+		for i, p := range []int{1, 2} {
+			res := solve(s, p, data)
+			total += res.elapsed
+			switch {
+			case res.err != nil:
+				cells[i] = "error: " + position(path, res.err)
+				failed = true
+			case strings.Contains(string(res.answer), "\n"):
+				cells[i] = "(see below)"
+				multiline = append(multiline, fmt.Sprintf("Day %d part %d:\n%s", day, p, res.answer))
+			default:
+				cells[i] = string(res.answer)
+			}
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t\n", day, cells[0], cells[1], total.Round(time.Microsecond))
+	}
+
+	tw.Flush()
+	for _, m := range multiline {
+		fmt.Println()
+		fmt.Print(m)
+	}
+
+	if failed {
+		return errors.New("some days failed")
+	}
+	return nil
+}
+
+func solve(s solver.Solver, part int, data []byte) result {
+	start := time.Now()
+	answer, err := solver.Solve(s, part, bytes.NewReader(data))
+	return result{answer, err, time.Since(start)}
+}
+
+func printAnswer(part int, answer solver.Answer) {
+	if strings.Contains(string(answer), "\n") {
+		fmt.Printf("Part %d:\n%s", part, answer)
+		return
+	}
+	fmt.Printf("Part %d: %s\n", part, answer)
+}
+
+func defaultInput(dir string, day int) string {
+	return filepath.Join(dir, fmt.Sprintf("day%02d", day), "input.txt")
+}
+
+func displayPath(path string) string {
+	if path == "-" {
+		return "<stdin>"
+	}
+	return path
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		var buf bytes.Buffer
+		_, err := buf.ReadFrom(os.Stdin)
+		return buf.Bytes(), err
+	}
+	return os.ReadFile(path)
+}
+
+// dayFromArgs returns the value of the -day flag without parsing the
+// remaining arguments.
+func dayFromArgs(args []string) (int, bool) {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "day" {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return 0, false
+			}
+			value = args[i+1]
+		}
+		day, err := strconv.Atoi(value)
+		return day, err == nil
+	}
+	return 0, false
+}
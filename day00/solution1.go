@@ -1,31 +1,32 @@
-package main
+// Package day00 is the template for a new day. Copy it to dayNN, rename the
+// package, register the day and add its import to days/days.go.
+package day00
 
 import (
-    "bufio"
-    "fmt"
-    "os"
-)
-
-func EachLineDo(f func(string)) error {
-    scanner := bufio.NewScanner(os.Stdin)
+    "io"
 
-    for scanner.Scan() {
-        line := scanner.Text()
-        f(line)
-    }
-
-    err := scanner.Err()
+    "aoc/input"
+    "aoc/solver"
+)
 
-    return err
+func init() {
+    solver.Register(0, Solver{})
 }
 
-func main() {
-    err := EachLineDo(func(line string) {
+type Solver struct{}
+
+func (Solver) Part1(r io.Reader) (solver.Answer, error) {
+    err := input.EachLineDo(r, func(line string) {
         // TODO process line
     })
 
     if err != nil {
-        fmt.Fprintf(os.Stderr, "reading stdin:", err)
-        os.Exit(1)
+        return "", err
     }
+
+    return "", nil
+}
+
+func (Solver) Part2(r io.Reader) (solver.Answer, error) {
+    return "", nil
 }
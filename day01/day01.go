@@ -0,0 +1,53 @@
+// Package day01 solves "Calorie Counting".
+package day01
+
+import (
+    "errors"
+    "io"
+
+    "aoc/input"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(1, Solver{})
+}
+
+type Solver struct{}
+
+func max(ns []int) int {
+    ans := ns[0]
+    for i := 1; i < len(ns); i++ {
+        if ans < ns[i] {
+            ans = ns[i]
+        }
+    }
+    return ans
+}
+
+func sum(ns []int) int {
+    total := 0
+    for i := 0; i < len(ns); i++ {
+        total += ns[i]
+    }
+    return total
+}
+
+func ReadTotalCalories(r io.Reader) ([]int, error) {
+    var total_calories []int
+
+    err := input.EachBlock(r, func(elf []string) error {
+        calories, err := input.ParseInts(elf)
+        if err != nil {
+            return err
+        }
+        total_calories = append(total_calories, sum(calories))
+        return nil
+    })
+
+    if err == nil && len(total_calories) == 0 {
+        err = errors.New("no elves in input")
+    }
+
+    return total_calories, err
+}
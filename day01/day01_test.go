@@ -0,0 +1,29 @@
+package day01
+
+import (
+    "testing"
+
+    "aoc/solver/solvertest"
+)
+
+const example = `1000
+2000
+3000
+
+4000
+
+5000
+6000
+
+7000
+8000
+9000
+
+10000
+`
+
+func TestExamples(t *testing.T) {
+    solvertest.Run(t, Solver{}, []solvertest.Example{
+        {Name: "example", Input: example, Part1: "24000", Part2: "45000"},
+    })
+}
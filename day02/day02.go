@@ -0,0 +1,121 @@
+// Package day02 solves "Rock Paper Scissors".
+package day02
+
+import (
+    "io"
+
+    "aoc/input"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(2, Solver{})
+}
+
+type Solver struct{}
+
+type Shape int
+
+const (
+    Rock    Shape = 1
+    Paper   Shape = 2
+    Scissor Shape = 3
+)
+
+type Result int
+
+const (
+    Loss    Result = 0
+    Draw    Result = 3
+    Win     Result = 6
+)
+
+func (s *Shape) play(o Shape) Result {
+    switch *s {
+    case Rock:
+        switch o {
+        case Rock:
+            return Draw
+        case Paper:
+            return Loss
+        case Scissor:
+            return Win
+        default:
+            panic("error: can't find opponent's shape")
+        }
+    case Paper:
+        switch o {
+        case Rock:
+            return Win
+        case Paper:
+            return Draw
+        case Scissor:
+            return Loss
+        default:
+            panic("error: can't find opponent's shape")
+        }
+    case Scissor:
+        switch o {
+        case Rock:
+            return Loss
+        case Paper:
+            return Win
+        case Scissor:
+            return Draw
+        default:
+            panic("error: can't find opponent's shape")
+        }
+    default:
+        panic("error: can't find your shape")
+    }
+}
+
+func getOpponentShape(f input.Field) (Shape, error) {
+    switch f.Text {
+    case "A":
+        return Rock, nil
+    case "B":
+        return Paper, nil
+    case "C":
+        return Scissor, nil
+    default:
+        return 0, f.Errorf("can't find opponent's shape")
+    }
+}
+
+func sum(ns []int) int {
+    total := 0
+    for i := 0; i < len(ns); i++ {
+        total += ns[i]
+    }
+    return total
+}
+
+// PlayStrategy plays every round of the strategy guide read from r and
+// returns the scores of all rounds. The second column of a round is turned
+// into your shape by own.
+func PlayStrategy(r io.Reader, own func(opponent Shape, column input.Field) (Shape, error)) ([]int, error) {
+    var results []int
+
+    err := input.EachLine(r, func(line string) error {
+        split := input.Split(line, " ")
+        if len(split) != 2 {
+            return input.Errorf("expected two columns, got %d", len(split))
+        }
+        opponent, err := getOpponentShape(split[0])
+        if err != nil {
+            return err
+        }
+        own, err := own(opponent, split[1])
+        if err != nil {
+            return err
+        }
+
+        result := own.play(opponent)
+        total := int(result) + int(own)
+        results = append(results, total)
+        return nil
+    })
+
+    return results, err
+}
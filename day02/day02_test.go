@@ -0,0 +1,14 @@
+package day02
+
+import (
+    "testing"
+
+    "aoc/solver/solvertest"
+)
+
+func TestExamples(t *testing.T) {
+    solvertest.Run(t, Solver{}, []solvertest.Example{
+        {Name: "example", Input: "A Y\nB X\nC Z\n", Part1: "15", Part2: "12"},
+        {Name: "draws", Input: "A X\nB Y\nC Z\n", Part1: "15", Part2: "15"},
+    })
+}
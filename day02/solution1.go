@@ -1,121 +1,32 @@
-package main
+package day02
 
 import (
-    "fmt"
-    "os"
-    "bufio"
-    "strings"
-)
-
-type Shape int
-
-const (
-    Rock    Shape = 1
-    Paper   Shape = 2
-    Scissor Shape = 3
-)
-
-type Result int
+    "io"
 
-const (
-    Loss    Result = 0
-    Draw    Result = 3
-    Win     Result = 6
+    "aoc/input"
+    "aoc/solver"
 )
 
-func (s *Shape) play(o Shape) Result {
-    switch *s {
-    case Rock:
-        switch o {
-        case Rock:
-            return Draw
-        case Paper:
-            return Loss
-        case Scissor:
-            return Win
-        default:
-            panic("error: can't find opponent's shape")
-        }
-    case Paper:
-        switch o {
-        case Rock:
-            return Win
-        case Paper:
-            return Draw
-        case Scissor:
-            return Loss
-        default:
-            panic("error: can't find opponent's shape")
-        }
-    case Scissor:
-        switch o {
-        case Rock:
-            return Loss
-        case Paper:
-            return Win
-        case Scissor:
-            return Draw
-        default:
-            panic("error: can't find opponent's shape")
-        }
-    default:
-        panic("error: can't find your shape")
+func (Solver) Part1(r io.Reader) (solver.Answer, error) {
+    results, err := PlayStrategy(r, func(_ Shape, column input.Field) (Shape, error) {
+        return getOwnShape(column)
+    })
+    if err != nil {
+        return "", err
     }
-}
 
-func getOpponentShape(s string) Shape {
-    switch s {
-    case "A":
-        return Rock
-    case "B":
-        return Paper
-    case "C":
-        return Scissor
-    default:
-        panic("error: can't find opponent's shape")
-    }
+    return solver.Int(sum(results)), nil
 }
 
-func getOwnShape(s string) Shape {
-    switch s {
+func getOwnShape(f input.Field) (Shape, error) {
+    switch f.Text {
     case "X":
-        return Rock
+        return Rock, nil
     case "Y":
-        return Paper
+        return Paper, nil
     case "Z":
-        return Scissor
+        return Scissor, nil
     default:
-        panic("error: can't find your shape")
-    }
-}
-
-func sum(ns []int) int {
-    total := 0
-    for i := 0; i < len(ns); i++ {
-        total += ns[i]
-    }
-    return total
-}
-
-func main() {
-    scanner := bufio.NewScanner(os.Stdin)
-    var results []int
-
-    for scanner.Scan() {
-        line := scanner.Text()
-        split := strings.Split(line, " ")
-        opponent := getOpponentShape(split[0])
-        own := getOwnShape(split[1])
-
-        result := own.play(opponent)
-        total := int(result) + int(own)
-        results = append(results, total)
+        return 0, f.Errorf("can't find your shape")
     }
-
-    if err := scanner.Err(); err != nil {
-        fmt.Fprintln(os.Stderr, "reading stdin:", err)
-        return
-    }
-
-    fmt.Println("Total Score:", sum(results))
 }
@@ -1,148 +1,57 @@
-package main
+package day02
 
 import (
-    "fmt"
-    "os"
-    "bufio"
-    "strings"
-)
-
-type Shape int
-
-const (
-    Rock    Shape = 1
-    Paper   Shape = 2
-    Scissor Shape = 3
-)
+    "io"
 
-type Result int
-
-const (
-    Loss    Result = 0
-    Draw    Result = 3
-    Win     Result = 6
+    "aoc/input"
+    "aoc/solver"
 )
 
-func (s *Shape) play(o Shape) Result {
-    switch *s {
-    case Rock:
-        switch o {
-        case Rock:
-            return Draw
-        case Paper:
-            return Loss
-        case Scissor:
-            return Win
-        default:
-            panic("error: can't find opponent's shape")
-        }
-    case Paper:
-        switch o {
-        case Rock:
-            return Win
-        case Paper:
-            return Draw
-        case Scissor:
-            return Loss
-        default:
-            panic("error: can't find opponent's shape")
-        }
-    case Scissor:
-        switch o {
-        case Rock:
-            return Loss
-        case Paper:
-            return Win
-        case Scissor:
-            return Draw
-        default:
-            panic("error: can't find opponent's shape")
-        }
-    default:
-        panic("error: can't find your shape")
+func (Solver) Part2(r io.Reader) (solver.Answer, error) {
+    results, err := PlayStrategy(r, getOwnShapeForOutcome)
+    if err != nil {
+        return "", err
     }
-}
 
-func getOpponentShape(s string) Shape {
-    switch s {
-    case "A":
-        return Rock
-    case "B":
-        return Paper
-    case "C":
-        return Scissor
-    default:
-        panic("error: can't find opponent's shape")
-    }
+    return solver.Int(sum(results)), nil
 }
 
-func getOwnShape(opponent Shape, strategy string) Shape {
+func getOwnShapeForOutcome(opponent Shape, strategy input.Field) (Shape, error) {
     switch opponent {
     case Rock:
-        switch strategy {
+        switch strategy.Text {
         case "X":
-            return Scissor
+            return Scissor, nil
         case "Y":
-            return Rock
+            return Rock, nil
         case "Z":
-            return Paper
+            return Paper, nil
         default:
-            panic("error: can't find your shape")
+            return 0, strategy.Errorf("can't find your strategy")
         }
     case Paper:
-        switch strategy {
+        switch strategy.Text {
         case "X":
-            return Rock
+            return Rock, nil
         case "Y":
-            return Paper
+            return Paper, nil
         case "Z":
-            return Scissor
+            return Scissor, nil
         default:
-            panic("error: can't find your shape")
+            return 0, strategy.Errorf("can't find your strategy")
         }
     case Scissor:
-        switch strategy {
+        switch strategy.Text {
         case "X":
-            return Paper
+            return Paper, nil
         case "Y":
-            return Scissor
+            return Scissor, nil
         case "Z":
-            return Rock
+            return Rock, nil
         default:
-            panic("error: can't find your shape")
+            return 0, strategy.Errorf("can't find your strategy")
         }
     default:
-        panic("error: can't find opponent's shape")
-    }
-}
-
-func sum(ns []int) int {
-    total := 0
-    for i := 0; i < len(ns); i++ {
-        total += ns[i]
-    }
-    return total
-}
-
-func main() {
-    scanner := bufio.NewScanner(os.Stdin)
-    var results []int
-
-    for scanner.Scan() {
-        line := scanner.Text()
-        split := strings.Split(line, " ")
-        opponent := getOpponentShape(split[0])
-        own := getOwnShape(opponent, split[1])
-
-        result := own.play(opponent)
-        total := int(result) + int(own)
-        results = append(results, total)
+        panic("unreachable line")
     }
-
-    if err := scanner.Err(); err != nil {
-        fmt.Fprintln(os.Stderr, "reading stdin:", err)
-        return
-    }
-
-    fmt.Println("Total Score:", sum(results))
 }
@@ -0,0 +1,42 @@
+// Package day03 solves "Rucksack Reorganization".
+package day03
+
+import (
+    "aoc/input"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(3, Solver{})
+}
+
+type Solver struct{}
+
+func getPriority(r rune) int {
+    if r >= 97 {
+        return int(r - 96)
+    } else {
+        return int(r - 38)
+    }
+}
+
+// checkItems reports the first item of the rucksack which is not a letter.
+func checkItems(rucksack string) error {
+    if len(rucksack) == 0 {
+        return input.Errorf("empty rucksack")
+    }
+    for i, r := range rucksack {
+        if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+            return &input.ParseError{Column: i + 1, Text: string(r), Msg: "invalid item"}
+        }
+    }
+    return nil
+}
+
+func sum(ns []int) int {
+    total := 0
+    for _,n := range ns {
+        total += n
+    }
+    return total
+}
@@ -0,0 +1,36 @@
+package day03
+
+import (
+    "testing"
+
+    "aoc/solver/solvertest"
+)
+
+const example = `vJrwpWtwJgWrhcsFMMfFFhFp
+jqHRNqRjqzjGDLGLrsFMfFZSrLrFZsSL
+PmmdzqPrVvPwwTWBwg
+wMqvLMZHhHMvwLHjbvcjnnSBnvTQFn
+ttgJtRGJQctTZtZT
+CrZsJsPPZsGzwwsLwLmpwMDw
+`
+
+func TestExamples(t *testing.T) {
+    solvertest.Run(t, Solver{}, []solvertest.Example{
+        {Name: "example", Input: example, Part1: "157", Part2: "70"},
+    })
+}
+
+func TestGetPriority(t *testing.T) {
+    tests := []struct {
+        item rune
+        want int
+    }{
+        {'a', 1}, {'p', 16}, {'z', 26}, {'A', 27}, {'L', 38}, {'Z', 52},
+    }
+
+    for _, tt := range tests {
+        if got := getPriority(tt.item); got != tt.want {
+            t.Errorf("getPriority(%q) = %d, want %d", tt.item, got, tt.want)
+        }
+    }
+}
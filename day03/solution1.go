@@ -1,41 +1,41 @@
-package main
+package day03
 
 import (
-    "bufio"
-    "fmt"
-    "os"
+    "io"
     "unicode/utf8"
+
+    "aoc/input"
+    "aoc/solver"
 )
 
-func main() {
+func (Solver) Part1(r io.Reader) (solver.Answer, error) {
     var priorities []int
 
-    scanner := bufio.NewScanner(os.Stdin)
+    err := input.EachLine(r, func(rucksack string) error {
+        if err := checkItems(rucksack); err != nil {
+            return err
+        }
+        if len(rucksack) % 2 != 0 {
+            return input.Errorf("compartments differ in size, %d items", len(rucksack))
+        }
 
-    for scanner.Scan() {
-        rucksack := scanner.Text()
-        sameItem := findSameItemInRucksack(rucksack)
+        sameItem, err := findSameItemInRucksack(rucksack)
+        if err != nil {
+            return err
+        }
         priority := getPriority(sameItem)
         priorities = append(priorities, priority)
-    }
+        return nil
+    })
 
-    if err := scanner.Err(); err != nil {
-        fmt.Fprintf(os.Stderr, "error reading stdin:", err)
-        os.Exit(1)
+    if err != nil {
+        return "", err
     }
 
-    fmt.Println("Sum of priorities:", sum(priorities))
+    return solver.Int(sum(priorities)), nil
 }
 
-func getPriority(r rune) int {
-    if r >= 97 {
-        return int(r - 96)
-    } else {
-        return int(r - 38)
-    }
-}
-
-func findSameItemInRucksack(rucksack string) rune {
+func findSameItemInRucksack(rucksack string) (rune, error) {
     firstCompartment := rucksack[:len(rucksack)/2]
     secondCompartment := rucksack[len(rucksack)/2:]
 
@@ -52,17 +52,9 @@ func findSameItemInRucksack(rucksack string) rune {
         w = width
         _, ok := compareSet[runeValue]
         if ok {
-            return runeValue
+            return runeValue, nil
         }
     }
 
-    panic("No similiar item in both compartments")
-}
-
-func sum(ns []int) int {
-    total := 0
-    for _,n := range ns {
-        total += n
-    }
-    return total
+    return 0, input.Errorf("no similiar item in both compartments")
 }
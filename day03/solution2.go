@@ -1,60 +1,53 @@
-package main
+package day03
 
 import (
-    "bufio"
     "fmt"
-    "os"
-    "unicode/utf8"
+    "io"
     "strings"
+    "unicode/utf8"
+
+    "aoc/input"
+    "aoc/solver"
 )
 
-func main() {
+func (Solver) Part2(r io.Reader) (solver.Answer, error) {
     var (
         priorities []int
-        groups     [][]string
+        group      []string
     )
 
-    groupIdx := 0
-    rucksackIdx := 0
-    scanner := bufio.NewScanner(os.Stdin)
-
-    for scanner.Scan() {
-        if rucksackIdx == 0 {
-            groups = append(groups, make([]string, 3))
+    err := input.EachLine(r, func(rucksack string) error {
+        if err := checkItems(rucksack); err != nil {
+            return err
         }
 
-        rucksack := scanner.Text()
-        groups[groupIdx][rucksackIdx] = rucksack
-
-        rucksackIdx = (rucksackIdx + 1) % 3
-        if rucksackIdx == 0 {
-            groupIdx++
+        group = append(group, rucksack)
+        if len(group) < 3 {
+            return nil
         }
-    }
-
-    if err := scanner.Err(); err != nil {
-        fmt.Fprintf(os.Stderr, "error reading stdin:", err)
-        os.Exit(1)
-    }
 
-    for _, group := range groups {
-        sameItem := findSameItemInGroup(group)
+        sameItem, err := findSameItemInGroup(group)
+        if err != nil {
+            return err
+        }
         priority := getPriority(sameItem)
         priorities = append(priorities, priority)
-    }
+        group = nil
+        return nil
+    })
 
-    fmt.Println("Sum of priorities:", sum(priorities))
-}
+    if err != nil {
+        return "", err
+    }
 
-func getPriority(r rune) int {
-    if r >= 97 {
-        return int(r - 96)
-    } else {
-        return int(r - 38)
+    if len(group) != 0 {
+        return "", fmt.Errorf("incomplete group of %d rucksacks at end of input", len(group))
     }
+
+    return solver.Int(sum(priorities)), nil
 }
 
-func findSameItemInGroup(group []string) rune {
+func findSameItemInGroup(group []string) (rune, error) {
     var sameItems string
 
     sameItems = findSimiliarItemsInTwoRucksack(group[0], group[1])
@@ -62,10 +55,10 @@ func findSameItemInGroup(group []string) rune {
 
     if len(sameItems) == 1 {
         runeValue, _ := utf8.DecodeRuneInString(sameItems)
-        return runeValue
+        return runeValue, nil
     }
 
-    panic("More than one same item in group")
+    return 0, input.Errorf("%d same items in group, expected one badge", len(sameItems))
 }
 
 func findSimiliarItemsInTwoRucksack(a, b string) string {
@@ -119,11 +112,3 @@ func getUniqueItems(rucksack string) string {
 
     return sb.String()
 }
-
-func sum(ns []int) int {
-    total := 0
-    for _,n := range ns {
-        total += n
-    }
-    return total
-}
@@ -0,0 +1,65 @@
+// Package day04 solves "Camp Cleanup".
+package day04
+
+import (
+    "io"
+
+    "aoc/input"
+    "aoc/interval"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(4, Solver{})
+}
+
+type Solver struct{}
+
+// Range is the section assignment of an elf.
+type Range = interval.Interval
+
+func StringToRange(arr input.Field) (Range, error) {
+    split := arr.Split("-")
+    if len(split) != 2 {
+        return Range{}, arr.Errorf("expected range <start>-<end>")
+    }
+    start, err := split[0].Int()
+    if err != nil {
+        return Range{}, err
+    }
+    end, err := split[1].Int()
+    if err != nil {
+        return Range{}, err
+    }
+    if start > end {
+        return Range{}, arr.Errorf("range ends before it starts")
+    }
+    return interval.New(start, end), nil
+}
+
+// CountPairs returns the number of section assignment pairs read from r
+// for which match holds.
+func CountPairs(r io.Reader, match func(first, second Range) bool) (int, error) {
+    count := 0
+
+    err := input.EachLine(r, func(line string) error {
+        rawPair := input.Split(line, ",")
+        if len(rawPair) != 2 {
+            return input.Errorf("expected a pair of ranges")
+        }
+
+        first, err := StringToRange(rawPair[0])
+        if err != nil {
+            return err
+        }
+        second, err := StringToRange(rawPair[1])
+        if err != nil {
+            return err
+        }
+
+        if match(first, second) { count++ }
+        return nil
+    })
+
+    return count, err
+}
@@ -0,0 +1,71 @@
+package day04
+
+import (
+    "errors"
+    "strings"
+    "testing"
+
+    "aoc/input"
+    "aoc/solver/solvertest"
+)
+
+const example = `2-4,6-8
+2-3,4-5
+5-7,7-9
+2-8,3-7
+6-6,4-6
+2-6,4-8
+`
+
+func TestExamples(t *testing.T) {
+    solvertest.Run(t, Solver{}, []solvertest.Example{
+        {Name: "example", Input: example, Part1: "2", Part2: "4"},
+    })
+}
+
+func TestRangeOverlaps(t *testing.T) {
+    tests := []struct {
+        a, b string
+        want bool
+    }{
+        {"2-4", "6-8", false},
+        {"5-7", "7-9", true},
+        {"2-8", "3-7", true},
+        {"3-7", "2-8", true},
+        {"6-6", "4-6", true},
+        {"4-6", "7-9", false},
+    }
+
+    for _, tt := range tests {
+        a, err := StringToRange(input.Line(tt.a))
+        if err != nil {
+            t.Fatal(err)
+        }
+        b, err := StringToRange(input.Line(tt.b))
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got := a.Overlaps(b); got != tt.want {
+            t.Errorf("%s.Overlaps(%s) = %v, want %v", tt.a, tt.b, got, tt.want)
+        }
+    }
+}
+
+func TestMalformedInput(t *testing.T) {
+    tests := []struct {
+        input string
+        line, column int
+    }{
+        {"2-4,6-8\n2-x,4-5\n", 2, 3},
+        {"2-4,6-8\n5-7\n", 2, 0},
+        {"2-4,8-6\n", 1, 5},
+    }
+
+    for _, tt := range tests {
+        _, err := Solver{}.Part1(strings.NewReader(tt.input))
+        var pe *input.ParseError
+        if !errors.As(err, &pe) || pe.Line != tt.line || pe.Column != tt.column {
+            t.Errorf("Part1(%q) error = %v, want parse error at %d:%d", tt.input, err, tt.line, tt.column)
+        }
+    }
+}
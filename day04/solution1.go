@@ -1,52 +1,18 @@
-package main
+package day04
 
 import (
-    "bufio"
-    "fmt"
-    "os"
-    "strings"
-    "strconv"
-)
-
-type Range struct {
-    start int
-    end int
-}
-
-func (r *Range) Contains(other *Range) bool {
-    return r.start <= other.start && r.end >= other.end
-}
-
-func StringToRange(arr string) *Range {
-    split := strings.Split(arr, "-")
-    start, _ := strconv.Atoi(split[0])
-    end, _ := strconv.Atoi(split[1])
-    return &Range{start, end}
-}
-
+    "io"
 
+    "aoc/solver"
+)
 
-func main() {
-    scanner := bufio.NewScanner(os.Stdin)
-    count := 0
-
-    for scanner.Scan() {
-        line := scanner.Text()
-
-        rawPair := strings.Split(line, ",")
-
-        first := StringToRange(rawPair[0])
-        second := StringToRange(rawPair[1])
-
-        if first.Contains(second) || second.Contains(first) {
-            count++
-        }
-    }
-
-    if err := scanner.Err(); err != nil {
-        fmt.Fprintln(os.Stderr, "reading stdin:", err)
-        os.Exit(1)
+func (Solver) Part1(r io.Reader) (solver.Answer, error) {
+    count, err := CountPairs(r, func(first, second Range) bool {
+        return first.Covers(second) || second.Covers(first)
+    })
+    if err != nil {
+        return "", err
     }
 
-    fmt.Println("Total num of fully containing pairs:", count)
+    return solver.Int(count), nil
 }
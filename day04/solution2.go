@@ -1,58 +1,18 @@
-package main
+package day04
 
 import (
-    "bufio"
-    "fmt"
-    "os"
-    "strings"
-    "strconv"
-)
-
-type Range struct {
-    start int
-    end int
-}
-
-func (r *Range) Contains(other *Range) bool {
-    return r.start <= other.start && r.end >= other.end
-}
-
-func (r *Range) Overlaps(other *Range) bool {
-    return (
-        r.Contains(other) ||
-        other.Contains(r) ||
-        (r.start >= other.start && r.start <= other.end) ||
-        (r.end   >= other.start && r.end   <= other.end))
-}
-
-func StringToRange(arr string) *Range {
-    split := strings.Split(arr, "-")
-    start, _ := strconv.Atoi(split[0])
-    end, _ := strconv.Atoi(split[1])
-    return &Range{start, end}
-}
+    "io"
 
+    "aoc/solver"
+)
 
-
-func main() {
-    scanner := bufio.NewScanner(os.Stdin)
-    overlappingCount := 0
-
-    for scanner.Scan() {
-        line := scanner.Text()
-
-        rawPair := strings.Split(line, ",")
-
-        first := StringToRange(rawPair[0])
-        second := StringToRange(rawPair[1])
-
-        if first.Overlaps(second) { overlappingCount++ }
-    }
-
-    if err := scanner.Err(); err != nil {
-        fmt.Fprintln(os.Stderr, "reading stdin:", err)
-        os.Exit(1)
+func (Solver) Part2(r io.Reader) (solver.Answer, error) {
+    overlappingCount, err := CountPairs(r, func(first, second Range) bool {
+        return first.Overlaps(second)
+    })
+    if err != nil {
+        return "", err
     }
 
-    fmt.Println("Total num of overlapping pairs:", overlappingCount)
+    return solver.Int(overlappingCount), nil
 }
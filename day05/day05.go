@@ -0,0 +1,201 @@
+// Package day05 solves "Supply Stacks".
+package day05
+
+import (
+    "errors"
+    "io"
+    "strings"
+
+    "aoc/input"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(5, Solver{})
+}
+
+type Solver struct{}
+
+// --------------------------------- Rearrange --------------------------------
+
+// Rearrange reads the starting stacks and the rearrangement procedure from
+// r, applies every move with makeMove and returns the crates ending up on
+// top of each stack. Input without stacks, or leaving a stack empty, is
+// reported as ParseError.
+func Rearrange(r io.Reader, makeMove func(Move, []Stack) error) (string, error) {
+    var stacks []Stack
+    cargoPhase := true
+    movePhase := false
+
+    startMoving := func() {
+        cargoPhase = false
+        movePhase = true
+        for _, stack := range stacks { stack.Reverse() }
+    }
+
+    err := input.EachLine(r, func(line string) error {
+        if len(line) == 0 && cargoPhase { startMoving(); return nil }
+
+        if cargoPhase {
+            // every stack takes up four columns: "[X] "
+            if stacks == nil { stacks = make([]Stack, (len(line) + 1) / 4) }
+            return ParseCrateLine(line, stacks)
+        }
+
+        if movePhase {
+            move, err := ParseCommandLine(line, len(stacks))
+            if err != nil {
+                return err
+            }
+            if n := stacks[move.from].Len(); n < move.amount {
+                return input.Errorf("can't move %d crates from stack %d holding %d", move.amount, move.from+1, n)
+            }
+            return makeMove(*move, stacks)
+        }
+
+        return nil
+    })
+
+    if err != nil {
+        return "", err
+    }
+
+    if cargoPhase { startMoving() }
+
+    if len(stacks) == 0 {
+        return "", input.Errorf("no stacks")
+    }
+
+    var sb strings.Builder
+    for i, stack := range stacks {
+        head, ok := stack.Head()
+        if !ok {
+            return "", input.Errorf("stack %d is empty", i+1)
+        }
+        sb.WriteString(head.name)
+    }
+
+    return sb.String(), nil
+}
+
+// ---------------------------------- Model -----------------------------------
+
+//// --------------------------------- Crate ----------------------------------
+
+type Crate struct {
+    name string
+}
+
+//// --------------------------------- Stack ----------------------------------
+
+// ErrEmptyStack is returned when taking a crate from an empty stack.
+var ErrEmptyStack = errors.New("stack is empty")
+
+type Stack struct {
+    data []Crate
+}
+
+func NewStack() *Stack {
+    return &Stack{}
+}
+
+func (s *Stack) Push(c Crate) {
+    s.data = append(s.data, c)
+}
+
+func (s *Stack) Pop() error {
+    if len(s.data) == 0 {
+        return ErrEmptyStack
+    }
+
+    s.data = s.data[:len(s.data)-1]
+    return nil
+}
+
+func (s *Stack) Len() int {
+    return len(s.data)
+}
+
+// Head returns the crate on top of the stack, ok is false if the stack is
+// empty.
+func (s *Stack) Head() (c Crate, ok bool) {
+    if len(s.data) == 0 {
+        return Crate{}, false
+    }
+
+    return s.data[len(s.data)-1], true
+}
+
+func (s *Stack) Reverse() {
+    // from: https://golangcookbook.com/chapters/arrays/reverse/
+    for i, j := 0, len(s.data)-1; i < j; i, j = i+1, j-1 {
+        s.data[i], s.data[j] = s.data[j], s.data[i]
+    }
+}
+
+//// -------------------------------- Commands --------------------------------
+
+type Move struct {
+    amount int
+    from int
+    to int
+}
+
+func NewMove(amount, from, to int) *Move {
+    return &Move{amount, from, to}
+}
+
+// --------------------------------- Parsing ----------------------------------
+
+func ParseCrateLine(line string, stacks []Stack) error {
+    parens := false
+    for idx, char := range line {
+        switch char {
+        case '[':
+            parens = true
+        case ']':
+            parens = false
+        default:
+            if parens {
+                if (idx - 1) / 4 >= len(stacks) {
+                    return &input.ParseError{Column: idx + 1, Text: string(char), Msg: "crate outside of the stacks"}
+                }
+                crate := Crate{string(char)}
+                stacks[(idx - 1) / 4].Push(crate)
+            }
+        }
+    }
+    return nil
+}
+
+// ParseCommandLine parses a line like "move 1 from 2 to 1" moving crates
+// between nStacks stacks.
+func ParseCommandLine(line string, nStacks int) (*Move, error) {
+    split := input.Split(line, " ")
+    if len(split) != 6 {
+        return nil, input.Errorf("expected \"move <amount> from <stack> to <stack>\"")
+    }
+    for i, keyword := range []string{"move", "from", "to"} {
+        if err := split[2*i].Expect(keyword); err != nil {
+            return nil, err
+        }
+    }
+
+    var numbers [3]int
+    for i := range numbers {
+        n, err := split[2*i+1].Int()
+        if err != nil {
+            return nil, err
+        }
+        if i > 0 && (n < 1 || n > nStacks) {
+            return nil, split[2*i+1].Errorf("no such stack")
+        }
+        numbers[i] = n
+    }
+
+    amount, from, to := numbers[0], numbers[1], numbers[2]
+    if amount < 0 {
+        return nil, split[1].Errorf("negative amount")
+    }
+    return NewMove(amount, from-1, to-1), nil
+}
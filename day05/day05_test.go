@@ -0,0 +1,53 @@
+package day05
+
+import (
+    "errors"
+    "strings"
+    "testing"
+
+    "aoc/input"
+    "aoc/solver/solvertest"
+)
+
+const example = "" +
+    "    [D]    \n" +
+    "[N] [C]    \n" +
+    "[Z] [M] [P]\n" +
+    " 1   2   3 \n" +
+    "\n" +
+    "move 1 from 2 to 1\n" +
+    "move 3 from 1 to 3\n" +
+    "move 2 from 2 to 1\n" +
+    "move 1 from 1 to 2\n"
+
+func TestExamples(t *testing.T) {
+    solvertest.Run(t, Solver{}, []solvertest.Example{
+        {Name: "example", Input: example, Part1: "CMZ", Part2: "MCD"},
+    })
+}
+
+func TestMalformedInput(t *testing.T) {
+    crates := "    [D]    \n[N] [C]    \n[Z] [M] [P]\n 1   2   3 \n\n"
+    tests := []struct {
+        input string
+        line, column int
+    }{
+        {crates + "move 1 from 4 to 1\n", 6, 13},
+        {crates + "move 1 from 2 to\n", 6, 0},
+        {crates + "move x from 2 to 1\n", 6, 6},
+        {crates + "shift 1 from 2 to 1\n", 6, 1},
+        {crates + "move 5 from 2 to 1\n", 6, 0},
+        {"    [D]    \n[N] [C]    \n[Z] [M] [P] [Q]\n", 3, 14},
+        {"", 0, 0},
+        {"\nmove 1 from 1 to 1\n", 2, 13},
+        {"[A] [B]\n 1   2 \n\nmove 1 from 1 to 2\n", 0, 0},
+    }
+
+    for _, tt := range tests {
+        _, err := Solver{}.Part1(strings.NewReader(tt.input))
+        var pe *input.ParseError
+        if !errors.As(err, &pe) || pe.Line != tt.line || pe.Column != tt.column {
+            t.Errorf("Part1(%q) error = %v, want parse error at %d:%d", tt.input, err, tt.line, tt.column)
+        }
+    }
+}
@@ -0,0 +1,84 @@
+// Package day06 solves "Tuning Trouble".
+package day06
+
+import (
+    "errors"
+    "io"
+    "unicode/utf8"
+
+    "aoc/input"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(6, Solver{})
+}
+
+type Solver struct{}
+
+const (
+    PACKET_MARKER = 4
+    MESSAGE_MARKER = 14
+)
+
+func AllUnequal(rs []rune) bool {
+    nrs := len(rs)
+    for i, r := range rs[:nrs-1] {
+        for _, rr := range rs[i+1:] {
+            if r == rr {
+                return false
+            }
+        }
+    }
+    return true
+}
+
+// FindMarker returns the number of characters read from r until the first
+// `memory` characters in a row are all different.
+func FindMarker(r io.Reader, memory int) (int, error) {
+    var idx int
+
+    ring := make([]rune, memory)
+
+    err := input.EachLine(r, func(line string) error {
+        if utf8.RuneCountInString(line) < memory {
+            return input.Errorf("datastream shorter than the %d character marker", memory)
+        }
+
+        w := 0
+        for i := 0; i < memory; i++ {
+            runeValue, width := utf8.DecodeRuneInString(line[w:])
+            w += width
+            ring[i] = runeValue
+        }
+
+        idx = memory
+
+        if AllUnequal(ring) {
+            return nil
+        }
+
+        for i, c := range line[memory:] {
+            idx = i + memory
+            ring[i%memory] = c
+
+            if AllUnequal(ring) {
+                idx++
+                return nil
+            }
+        }
+
+        idx = 0
+        return nil
+    })
+
+    if err != nil {
+        return 0, err
+    }
+
+    if idx == 0 {
+        return 0, errors.New("no marker found")
+    }
+
+    return idx, nil
+}
@@ -0,0 +1,17 @@
+package day06
+
+import (
+    "testing"
+
+    "aoc/solver/solvertest"
+)
+
+func TestExamples(t *testing.T) {
+    solvertest.Run(t, Solver{}, []solvertest.Example{
+        {Name: "mjqj", Input: "mjqjpqmgbljsphdztnvjfqwrcgsmlb\n", Part1: "7", Part2: "19"},
+        {Name: "bvwb", Input: "bvwbjplbgvbhsrlpgdmjqwftvncz\n", Part1: "5", Part2: "23"},
+        {Name: "nppd", Input: "nppdvjthqldpwncqszvftbrmjlhg\n", Part1: "6", Part2: "23"},
+        {Name: "nznr", Input: "nznrnfrfntjfmvfwmzdfjlvtqnbhcprsg\n", Part1: "10", Part2: "29"},
+        {Name: "zcfz", Input: "zcfzfwzzqfrljwzlrfnpqdbhtmscgvjw\n", Part1: "11", Part2: "26"},
+    })
+}
@@ -0,0 +1,180 @@
+// Package day07 solves "No Space Left On Device".
+package day07
+
+import (
+    "io"
+    "strings"
+
+    "aoc/input"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(7, Solver{})
+}
+
+type Solver struct{}
+
+type File struct {
+    Name string
+    Size int
+}
+
+func NewFile(name string, size int) *File {
+    return &File{name, size}
+}
+
+
+
+type Dir struct {
+    Name string
+    Dirs []*Dir
+    Files []*File
+    Parent *Dir
+}
+
+func NewDir(name string) *Dir {
+    return &Dir{
+        name,
+        make([]*Dir, 0),
+        make([]*File, 0),
+        nil,
+    }
+}
+
+func (d *Dir) AddDir(newDir *Dir) {
+    newDir.Parent = d
+    d.Dirs = append(d.Dirs, newDir)
+}
+
+func (d *Dir) AddFile(newFile *File) {
+    d.Files = append(d.Files, newFile)
+}
+
+func (d *Dir) ExistsFile(name string) bool {
+    for _, file := range d.Files {
+        if file.Name == name {
+            return true
+        }
+    }
+    return false
+}
+
+func (d *Dir) ExistsDir(name string) bool {
+    for _, dir := range d.Dirs {
+        if dir.Name == name {
+            return true
+        }
+    }
+    return false
+}
+
+func (d *Dir) GetDir(name string) *Dir {
+    for _, dir := range d.Dirs {
+        if dir.Name == name {
+            return dir
+        }
+    }
+    return nil
+}
+
+func (d *Dir) Size() int {
+    var total = 0
+    for _, file := range d.Files {
+        total += file.Size
+    }
+    for _, dir := range d.Dirs {
+        total += dir.Size()
+    }
+    return total
+}
+
+func (d *Dir) Walk(observe func(*Dir)) {
+    observe(d)
+    for _, dir := range d.Dirs {
+        dir.Walk(observe)
+    }
+}
+
+// ReadFileSystem replays the terminal output read from r and returns the
+// root directory of the explored file system.
+func ReadFileSystem(r io.Reader) (*Dir, error) {
+    root := NewDir("/")
+    cwd := root
+    lastCommand := ""
+
+    err := input.EachLine(r, func(line string) error {
+        if len(line) == 0 {
+            return input.Errorf("empty line")
+        }
+
+        if line[0] == '$' {
+            split := input.Split(line, " ")
+            if len(split) < 2 {
+                return input.Errorf("missing command")
+            }
+            cmd := split[1]
+            args := strings.Join(strings.Split(line, " ")[2:], " ")
+
+            switch cmd.Text {
+            case "cd":
+                switch args {
+                case "":
+                    return cmd.Errorf("missing directory")
+                case "/":
+                    cwd = root
+                case "..":
+                    if cwd.Parent == nil {
+                        return cmd.Errorf("can't leave the root directory")
+                    }
+                    cwd = cwd.Parent
+                default:
+                    dir := cwd.GetDir(args)
+                    if dir == nil {
+                        dir = NewDir(args)
+                        cwd.AddDir(dir)
+                    }
+                    cwd = dir
+                }
+            case "ls":
+                ;
+            default:
+                return cmd.Errorf("unknown command")
+            }
+
+            lastCommand = cmd.Text
+        } else {
+            if lastCommand != "ls" {
+                return input.Errorf("output without ls command")
+            }
+
+            split := input.Split(line, " ")
+            if len(split) != 2 {
+                return input.Errorf("expected \"dir <name>\" or \"<size> <name>\"")
+            }
+            if split[0].Text == "dir" {
+                dirName := split[1].Text
+                if !cwd.ExistsDir(dirName) {
+                    cwd.AddDir(NewDir(dirName))
+                }
+            } else {
+                fileName := split[1].Text
+                size, err := split[0].Int()
+                if err != nil {
+                    return err
+                }
+                if !cwd.ExistsFile(fileName) {
+                    cwd.AddFile(NewFile(fileName, size))
+                }
+            }
+        }
+
+        return nil
+    })
+
+    if err != nil {
+        return nil, err
+    }
+
+    return root, nil
+}
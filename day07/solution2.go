@@ -1,190 +1,29 @@
-package main
+package day07
 
 import (
-    "bufio"
-    "fmt"
-    "os"
-    "strings"
-    "strconv"
+    "io"
     "sort"
-)
-
-type File struct {
-    Name string
-    Size int
-}
-
-func NewFile(name string, size int) *File {
-    return &File{name, size}
-}
-
-
-
-type Dir struct {
-    Name string
-    Dirs []*Dir
-    Files []*File
-    Parent *Dir
-}
-
-func NewDir(name string) *Dir {
-    return &Dir{
-        name,
-        make([]*Dir, 0),
-        make([]*File, 0),
-        nil,
-    }
-}
-
-func (d *Dir) AddDir(newDir *Dir) {
-    newDir.Parent = d
-    d.Dirs = append(d.Dirs, newDir)
-}
-
-func (d *Dir) AddFile(newFile *File) {
-    d.Files = append(d.Files, newFile)
-}
-
-func (d *Dir) ExistsFile(name string) bool {
-    for _, file := range d.Files {
-        if file.Name == name {
-            return true
-        }
-    }
-    return false
-}
-
-func (d *Dir) ExistsDir(name string) bool {
-    for _, dir := range d.Dirs {
-        if dir.Name == name {
-            return true
-        }
-    }
-    return false
-}
-
-func (d *Dir) GetDir(name string) *Dir {
-    for _, dir := range d.Dirs {
-        if dir.Name == name {
-            return dir
-        }
-    }
-    return nil
-}
-
-func (d *Dir) Size() int {
-    var total = 0
-    for _, file := range d.Files {
-        total += file.Size
-    }
-    for _, dir := range d.Dirs {
-        total += dir.Size()
-    }
-    return total
-}
-
-func (d *Dir) Walk(observe func(*Dir)) {
-    observe(d)
-    for _, dir := range d.Dirs {
-        dir.Walk(observe)
-    }
-}
-
-func EachLineDo(f func(string)) error {
-    scanner := bufio.NewScanner(os.Stdin)
-
-    for scanner.Scan() {
-        line := scanner.Text()
-        f(line)
-    }
 
-    err := scanner.Err()
-
-    return err
-}
-
-func main() {
-
-    root := NewDir("/")
-    cwd := root
-    lastCommand := ""
-
-    err := EachLineDo(func(line string) {
-        if line[0] == '$' {
-            split := strings.Split(line, " ")
-            cmd := split[1]
-            args := strings.Join(split[2:], " ")
-
-            switch cmd {
-            case "cd":
-                switch args {
-                case "/":
-                    cwd = root
-                case "..":
-                    cwd = cwd.Parent
-                default:
-                    dir := cwd.GetDir(args)
-                    if dir == nil {
-                        dir = NewDir(args)
-                        cwd.AddDir(dir)
-                    }
-                    cwd = dir
-                }
-            case "ls":
-                ;
-            default:
-                ;
-            }
-
-            lastCommand = cmd
-        } else {
-            if lastCommand == "ls" {
-                split := strings.Split(line, " ")
-                if split[0] == "dir" {
-                    dirName := split[1]
-                    if !cwd.ExistsDir(dirName) {
-                        cwd.AddDir(NewDir(dirName))
-                    }
-                } else {
-                    fileName := split[1]
-                    if !cwd.ExistsFile(fileName) {
-                        size, err := strconv.Atoi(split[0])
-                        if err != nil {
-                            fmt.Fprintf(os.Stderr, "not an integer", split[0])
-                            os.Exit(1)
-                        }
-                        cwd.AddFile(NewFile(fileName, size))
-                    }
-                }
-            }
-        }
-    })
+    "aoc/solver"
+)
 
+func (Solver) Part2(r io.Reader) (solver.Answer, error) {
+    root, err := ReadFileSystem(r)
     if err != nil {
-        fmt.Fprintf(os.Stderr, "reading stdin:", err)
-        os.Exit(1)
+        return "", err
     }
 
     var unusedSpace = 70_000_000 - root.Size()
     var spaceToDelete = 30_000_000 - unusedSpace
 
-    candidates := make(map[string]int)
+    var candidates []int
     root.Walk(func(cwd *Dir) {
         var size = cwd.Size()
         if size >= spaceToDelete {
-            candidates[cwd.Name] = size
+            candidates = append(candidates, size)
         }
     })
-    dirs := make([]string, 0, len(candidates))
-    for dir := range candidates {
-        dirs = append(dirs, dir)
-    }
-    sort.SliceStable(dirs, func(i, j int) bool {
-        return candidates[dirs[i]] < candidates[dirs[j]]
-    })
+    sort.Ints(candidates)
 
-    fmt.Println("Unused Space:   ", unusedSpace)
-    fmt.Println("Used Space:     ", root.Size())
-    fmt.Println("Space to delete:", spaceToDelete)
-    fmt.Printf( "Deleting '%s' (dir, size=%d)\n", dirs[0], candidates[dirs[0]])
+    return solver.Int(candidates[0]), nil
 }
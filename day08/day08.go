@@ -0,0 +1,38 @@
+// Package day08 solves "Treetop Tree House".
+package day08
+
+import (
+    "io"
+
+    "aoc/grid"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(8, Solver{})
+}
+
+type Solver struct{}
+
+// Forest holds the height of every tree.
+type Forest = grid.Grid[int]
+
+// ReadGrid reads a rectangular map of tree heights, one digit per tree.
+func ReadGrid(r io.Reader) (*Forest, error) {
+    return grid.Digits(r)
+}
+
+// Visible reports whether the tree at p can be seen from outside of the
+// forest, i.e. whether all trees in one of the four directions are lower.
+func Visible(forest *Forest, p grid.Point) bool {
+    height := forest.At(p)
+    for _, dir := range grid.Dirs4 {
+        lower := func(_ grid.Point, other int) bool {
+            return other < height
+        }
+        if forest.Walk(p, dir, lower) {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,20 @@
+package day08
+
+import (
+    "testing"
+
+    "aoc/solver/solvertest"
+)
+
+const example = `30373
+25512
+65332
+33549
+35390
+`
+
+func TestExamples(t *testing.T) {
+    solvertest.Run(t, Solver{}, []solvertest.Example{
+        {Name: "example", Input: example, Part1: "21", Part2: "8"},
+    })
+}
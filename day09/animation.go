@@ -0,0 +1,134 @@
+package day09
+
+import (
+    "image"
+    "image/color"
+    "image/gif"
+    "io"
+
+    "aoc/geom"
+)
+
+// -------------------------------- Animation ---------------------------------
+
+// The colours of the animation, indices into palette.
+const (
+    colorEmpty = iota
+    colorVisited
+    colorStart
+    colorKnot
+    colorTail
+    colorHead
+)
+
+var palette = color.Palette{
+    colorEmpty: color.RGBA{0x10, 0x10, 0x18, 0xff},
+    colorVisited: color.RGBA{0x30, 0x50, 0x90, 0xff},
+    colorStart: color.RGBA{0xf0, 0xf0, 0xf0, 0xff},
+    colorKnot: color.RGBA{0xe0, 0xa0, 0x30, 0xff},
+    colorTail: color.RGBA{0x40, 0xd0, 0x60, 0xff},
+    colorHead: color.RGBA{0xe0, 0x30, 0x30, 0xff},
+}
+
+// Animation records the knots of a rope step by step and turns them into
+// an animated GIF.
+type Animation struct {
+    frames []frame
+    bounds geom.Rect
+    // visited is the number of visited positions recorded so far.
+    visited int
+}
+
+// frame holds the knots of a rope and the positions its tail visited for
+// the first time since the previous frame.
+type frame struct {
+    knots []geom.Point
+    visits []geom.Point
+}
+
+// Record adds a frame showing the knots of the rope and the positions it
+// visited.
+func (a *Animation) Record(r *Rope) {
+    f := frame{r.Knots(), r.visits[a.visited:]}
+    a.visited = len(r.visits)
+    a.frames = append(a.frames, f)
+    a.bounds = a.bounds.Union(geom.Bounds(f.knots...)).Union(geom.Bounds(f.visits...))
+}
+
+// Frames returns the number of frames recorded.
+func (a *Animation) Frames() int {
+    return len(a.frames)
+}
+
+// WriteGIF writes the frames as an animated GIF, every position taking
+// scale by scale pixels and every frame shown for delay hundredths of a
+// second. Only the first frame covers the whole area, the following ones
+// just the positions of the knots before and after the step and the
+// positions visited during it.
+func (a *Animation) WriteGIF(w io.Writer, scale, delay int) error {
+    var (
+        anim = &gif.GIF{Config: image.Config{
+            ColorModel: palette,
+            Width: a.bounds.Dx() * scale,
+            Height: a.bounds.Dy() * scale,
+        }}
+        visited = make(map[geom.Point]bool)
+        previous []geom.Point
+    )
+    for _, f := range a.frames {
+        knots := f.knots
+        for _, p := range f.visits {
+            visited[p] = true
+        }
+
+        area := a.bounds
+        if previous != nil {
+            area = geom.Bounds(previous...).Union(geom.Bounds(knots...)).Union(geom.Bounds(f.visits...))
+        }
+        previous = knots
+
+        rect := image.Rect(
+            (area.Min.X-a.bounds.Min.X)*scale, (area.Min.Y-a.bounds.Min.Y)*scale,
+            (area.Max.X-a.bounds.Min.X)*scale, (area.Max.Y-a.bounds.Min.Y)*scale,
+        )
+        img := image.NewPaletted(rect, palette)
+        for y := area.Min.Y; y < area.Max.Y; y++ {
+            for x := area.Min.X; x < area.Max.X; x++ {
+                p := geom.Pt(x, y)
+                c := uint8(colorEmpty)
+                switch {
+                case p == geom.Pt(0, 0):
+                    c = colorStart
+                case visited[p]:
+                    c = colorVisited
+                }
+                for i := len(knots) - 1; i >= 0; i-- {
+                    if knots[i] != p {
+                        continue
+                    }
+                    switch i {
+                    case 0:
+                        c = colorHead
+                    case len(knots) - 1:
+                        c = colorTail
+                    default:
+                        c = colorKnot
+                    }
+                }
+                fill(img, (x-a.bounds.Min.X)*scale, (y-a.bounds.Min.Y)*scale, scale, c)
+            }
+        }
+        anim.Image = append(anim.Image, img)
+        anim.Delay = append(anim.Delay, delay)
+        anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+    }
+    return gif.EncodeAll(w, anim)
+}
+
+func fill(img *image.Paletted, x, y, size int, c uint8) {
+    for dy := 0; dy < size; dy++ {
+        for dx := 0; dx < size; dx++ {
+            img.SetColorIndex(x+dx, y+dy, c)
+        }
+    }
+}
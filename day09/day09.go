@@ -0,0 +1,199 @@
+// Package day09 solves "Rope Bridge".
+package day09
+
+import (
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+
+    "aoc/geom"
+    "aoc/input"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(9, &Solver{Knots: DefaultKnots, Scale: 4, Delay: 5})
+}
+
+// DefaultKnots is the number of knots of the rope of part 2.
+const DefaultKnots = 10
+
+// Solver follows the tail of a rope of two knots in part 1 and of Knots
+// knots in part 2, DefaultKnots if not set. The knots follow the Rules,
+// the DefaultRules if nil.
+//
+// Part 2 can show how the rope moves: if Frames is set, a frame per step
+// is written to the file named by it, "-" for Out, os.Stdout if nil. If GIF
+// is set, an animated GIF with Scale pixels per position and Delay
+// hundredths of a second per step is written to the file named by it.
+type Solver struct {
+    Knots int
+    Rules *Rules
+    Frames string
+    GIF string
+    Scale int
+    Delay int
+    Out io.Writer
+}
+
+func (s *Solver) Flags(fs *flag.FlagSet) {
+    if s.Rules == nil {
+        rules := DefaultRules
+        s.Rules = &rules
+    }
+    fs.IntVar(&s.Knots, "knots", s.Knots, "number of knots of the rope in part 2")
+    fs.IntVar(&s.Rules.Slack, "slack", s.Rules.Slack, "distance a knot may lag behind without moving")
+    fs.BoolVar(&s.Rules.Diagonal, "diagonal", s.Rules.Diagonal, "let knots step diagonally")
+    fs.IntVar(&s.Rules.CatchUp, "catchup", s.Rules.CatchUp, "most steps a knot takes per move of its leader, 0 for no limit")
+    fs.StringVar(&s.Frames, "frames", s.Frames, "write a frame per step of part 2 to `file`, - for stdout")
+    fs.StringVar(&s.GIF, "gif", s.GIF, "write an animated GIF of part 2 to `file`")
+    fs.IntVar(&s.Scale, "scale", s.Scale, "pixels per position in the GIF")
+    fs.IntVar(&s.Delay, "delay", s.Delay, "hundredths of a second per step in the GIF")
+}
+
+// rope returns a rope of the given number of knots following the rules of
+// the solver.
+func (s Solver) rope(knots int) (*Rope, error) {
+    var rules = DefaultRules
+    if s.Rules != nil {
+        rules = *s.Rules
+    }
+    if err := rules.Validate(); err != nil {
+        return nil, err
+    }
+    if knots < 2 {
+        return nil, fmt.Errorf("a rope needs at least 2 knots, not %d", knots)
+    }
+    return NewRope(knots, rules), nil
+}
+
+// create creates the named file, "-" for Out, and returns it with a
+// function closing it.
+func (s Solver) create(name string) (io.Writer, func() error, error) {
+    if name == "-" {
+        out := s.Out
+        if out == nil {
+            out = os.Stdout
+        }
+        return out, func() error { return nil }, nil
+    }
+
+    f, err := os.Create(name)
+    if err != nil {
+        return nil, nil, err
+    }
+    return f, f.Close, nil
+}
+
+// Command moves the head by Steps cells, one step at a time or, if Jump
+// is set, all at once.
+type Command struct {
+    Direction geom.Dir
+    Steps int
+    Jump bool
+}
+
+func NewCommand(d geom.Dir, steps int) *Command {
+    return &Command{d, steps, false}
+}
+
+// String returns the command as written in the input, like "R 4".
+func (c *Command) String() string {
+    var letter = map[geom.Dir]string{geom.Up: "U", geom.Down: "D", geom.Left: "L", geom.Right: "R"}[c.Direction]
+    if letter == "" {
+        letter = c.Direction.String()
+    }
+    if c.Jump {
+        letter += "!"
+    }
+    return fmt.Sprintf("%s %d", letter, c.Steps)
+}
+
+func StringToDirection(f input.Field) (geom.Dir, error) {
+    switch f.Text {
+    case "U", "D", "L", "R":
+        return geom.ParseDir(f.Text)
+    default:
+        return 0, f.Errorf("invalid direction")
+    }
+}
+
+type Head struct {
+    pos geom.Point
+}
+
+func NewHead(x, y int) *Head {
+    return &Head{geom.Pt(x, y)}
+}
+
+func (h *Head) Move(direction geom.Dir) {
+    h.pos = h.pos.Add(direction.Step())
+}
+
+// Jump moves the head n cells in the given direction at once.
+func (h *Head) Jump(direction geom.Dir, n int) {
+    h.pos = h.pos.Add(direction.Step().Mul(n))
+}
+
+func (h *Head) Position() geom.Point {
+    return h.pos
+}
+
+type Tail struct {
+    pos geom.Point
+    Rules Rules
+}
+
+// NewTail returns a tail following the DefaultRules.
+func NewTail(x, y int) *Tail {
+    return &Tail{geom.Pt(x, y), DefaultRules}
+}
+
+func (t *Tail) Position() geom.Point {
+    return t.pos
+}
+
+// MoveTo lets the tail follow the knot at pos according to its rules and
+// returns the positions it stepped on, nil if it stayed.
+func (t *Tail) MoveTo(pos geom.Point) []geom.Point {
+    path := t.Rules.Follow(t.pos, pos)
+    if len(path) > 0 {
+        t.pos = path[len(path)-1]
+    }
+    return path
+}
+
+// ReadCommands reads one command per line like "R 4". A direction like
+// "D!" makes the head jump instead of stepping.
+func ReadCommands(r io.Reader) ([]*Command, error) {
+    var commands = make([]*Command, 0)
+    err := input.EachLine(r, func(line string) error {
+        split := input.Split(line, " ")
+        if len(split) != 2 {
+            return input.Errorf("expected \"<direction> <steps>\"")
+        }
+        var (
+            dir = split[0]
+            jump bool
+        )
+        dir.Text, jump = strings.CutSuffix(dir.Text, "!")
+        direction, err := StringToDirection(dir)
+        if err != nil {
+            return split[0].Errorf("invalid direction")
+        }
+        steps, err := split[1].Int()
+        if err != nil {
+            return err
+        }
+        if steps < 0 {
+            return split[1].Errorf("negative number of steps")
+        }
+        command := &Command{direction, steps, jump}
+        commands = append(commands, command)
+        return nil
+    })
+
+    return commands, err
+}
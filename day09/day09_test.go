@@ -0,0 +1,264 @@
+package day09
+
+import (
+    "bytes"
+    "fmt"
+    "image/gif"
+    "reflect"
+    "strings"
+    "testing"
+
+    "aoc/geom"
+    "aoc/solver/solvertest"
+)
+
+const example = `R 4
+U 4
+L 3
+D 1
+R 4
+D 1
+L 5
+R 2
+`
+
+const largerExample = `R 5
+U 8
+L 8
+D 3
+R 17
+D 10
+L 25
+U 20
+`
+
+func TestExamples(t *testing.T) {
+    solvertest.Run(t, Solver{}, []solvertest.Example{
+        {Name: "example", Input: example, Part1: "13", Part2: "1"},
+        {Name: "larger", Input: largerExample, Part2: "36"},
+    })
+}
+
+func TestKnots(t *testing.T) {
+    solvertest.Run(t, Solver{Knots: 2}, []solvertest.Example{
+        {Name: "two knots", Input: example, Part2: "13"},
+        {Name: "larger two knots", Input: largerExample, Part2: "88"},
+    })
+
+    if _, err := (Solver{Knots: 1}).Part2(strings.NewReader(example)); err == nil {
+        t.Error("Part2 with a single knot didn't fail")
+    }
+}
+
+func TestRope(t *testing.T) {
+    commands, err := ReadCommands(strings.NewReader(largerExample))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // The end of the larger example as drawn in the puzzle description,
+    // except that the visited positions are shown too.
+    rope := NewRope(10, DefaultRules)
+    rope.Run(commands, nil)
+    want := geom.Rect{Min: geom.Pt(-11, -15), Max: geom.Pt(15, 6)}
+    if got := rope.Frame(want); !strings.HasPrefix(got, "H.........................\n1.........................\n2.........................\n") {
+        t.Errorf("Frame =\n%s", got)
+    }
+    if got := rope.Tail(); got != geom.Pt(-11, -6) {
+        t.Errorf("Tail() = %v, want (-11,-6)", got)
+    }
+    if got := rope.Visited(); got != 36 {
+        t.Errorf("Visited() = %d, want 36", got)
+    }
+}
+
+func TestFrames(t *testing.T) {
+    var out strings.Builder
+    s := Solver{Knots: 3, Frames: "-", Out: &out}
+    if _, err := s.Part2(strings.NewReader("R 2\nU 2\n")); err != nil {
+        t.Fatal(err)
+    }
+
+    want := `== start ==
+H
+
+== R 2 (1/2) ==
+1H
+
+== R 2 (2/2) ==
+21H
+
+== U 2 (1/2) ==
+..H
+21.
+
+== U 2 (2/2) ==
+..H
+.21
+s..
+
+`
+    if out.String() != want {
+        t.Errorf("frames =\n%s\nwant\n%s", out.String(), want)
+    }
+}
+
+func TestGIF(t *testing.T) {
+    commands, err := ReadCommands(strings.NewReader(example))
+    if err != nil {
+        t.Fatal(err)
+    }
+    var anim Animation
+    NewRope(10, DefaultRules).Run(commands, func(r *Rope, _ *Command, _ int) {
+        anim.Record(r)
+    })
+    if anim.Frames() != 25 {
+        t.Errorf("%d frames, want a frame per step and the start", anim.Frames())
+    }
+
+    var buf bytes.Buffer
+    if err := anim.WriteGIF(&buf, 3, 10); err != nil {
+        t.Fatal(err)
+    }
+    img, err := gif.DecodeAll(&buf)
+    if err != nil {
+        t.Fatal(err)
+    }
+    // The head reaches x = 0..5 and y = -4..0.
+    if len(img.Image) != 25 || img.Config.Width != 6*3 || img.Config.Height != 5*3 {
+        t.Errorf("GIF of %d frames of %dx%d", len(img.Image), img.Config.Width, img.Config.Height)
+    }
+    // After the first step the head is at (1,0), the start is in the
+    // bottom left corner.
+    if got := img.Image[1].ColorIndexAt(1*3, 4*3); got != colorHead {
+        t.Errorf("frame 1 shows colour %d at the head", got)
+    }
+}
+
+func TestGIFCatchUp(t *testing.T) {
+    commands, err := ReadCommands(strings.NewReader("R! 5\n"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    var anim Animation
+    rope := NewRope(2, Rules{Slack: 1, Diagonal: true, CatchUp: 0})
+    rope.Run(commands, func(r *Rope, _ *Command, _ int) {
+        anim.Record(r)
+    })
+
+    var buf bytes.Buffer
+    if err := anim.WriteGIF(&buf, 1, 10); err != nil {
+        t.Fatal(err)
+    }
+    img, err := gif.DecodeAll(&buf)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // The tail catches up from 0 to 4 in a single frame, passing 1 to 3.
+    want := []uint8{colorStart, colorVisited, colorVisited, colorVisited, colorTail, colorHead}
+    for x, c := range want {
+        if got := img.Image[1].ColorIndexAt(x, 0); got != c {
+            t.Errorf("after the jump x = %d has colour %d, want %d", x, got, c)
+        }
+    }
+    if rope.Visited() != 5 {
+        t.Errorf("Visited() = %d, want 5", rope.Visited())
+    }
+}
+
+func TestRules(t *testing.T) {
+    var (
+        loose = Rules{Slack: 2, Diagonal: true, CatchUp: 1}
+        orthogonal = Rules{Slack: 1, Diagonal: false, CatchUp: 0}
+        eager = Rules{Slack: 1, Diagonal: true, CatchUp: 0}
+    )
+    tests := []struct {
+        name string
+        rules Rules
+        leader geom.Point
+        want []geom.Point
+    }{
+        {"touching", DefaultRules, geom.Pt(1, 1), nil},
+        {"straight", DefaultRules, geom.Pt(2, 0), []geom.Point{geom.Pt(1, 0)}},
+        {"diagonal", DefaultRules, geom.Pt(1, -2), []geom.Point{geom.Pt(1, -1)}},
+        {"jump lags", DefaultRules, geom.Pt(0, 5), []geom.Point{geom.Pt(0, 1)}},
+        {"slack", loose, geom.Pt(2, -2), nil},
+        {"beyond slack", loose, geom.Pt(3, 1), []geom.Point{geom.Pt(1, 1)}},
+        {"orthogonal", orthogonal, geom.Pt(2, 1), []geom.Point{geom.Pt(1, 0)}},
+        {"orthogonal tie", orthogonal, geom.Pt(-2, 2), []geom.Point{geom.Pt(-1, 0), geom.Pt(-1, 1)}},
+        {"jump caught up", eager, geom.Pt(0, 5), []geom.Point{geom.Pt(0, 1), geom.Pt(0, 2), geom.Pt(0, 3), geom.Pt(0, 4)}},
+        {"diagonal jump", eager, geom.Pt(2, 4), []geom.Point{geom.Pt(1, 1), geom.Pt(2, 2), geom.Pt(2, 3)}},
+        {"bounded catch-up", Rules{Slack: 0, Diagonal: true, CatchUp: 2}, geom.Pt(-4, 0), []geom.Point{geom.Pt(-1, 0), geom.Pt(-2, 0)}},
+        {"onto the leader", Rules{Slack: 0, Diagonal: false}, geom.Pt(1, 1), []geom.Point{geom.Pt(1, 0), geom.Pt(1, 1)}},
+    }
+
+    for _, tt := range tests {
+        tail := NewTail(0, 0)
+        tail.Rules = tt.rules
+        got := tail.MoveTo(tt.leader)
+        if !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("%s: MoveTo(%v) = %v, want %v", tt.name, tt.leader, got, tt.want)
+        }
+        if want := geom.Pt(0, 0); len(tt.want) > 0 {
+            want = tt.want[len(tt.want)-1]
+            if tail.Position() != want {
+                t.Errorf("%s: tail at %v, want %v", tt.name, tail.Position(), want)
+            }
+        }
+    }
+
+    for _, rules := range []Rules{{Slack: -1}, {Slack: 1, CatchUp: -1}} {
+        s := Solver{Rules: &rules}
+        if _, err := s.Part1(strings.NewReader(example)); err == nil {
+            t.Errorf("Part1 with %+v didn't fail", rules)
+        }
+    }
+}
+
+func TestJumps(t *testing.T) {
+    commands, err := ReadCommands(strings.NewReader("D! 5\nR 2\nL! 4\n"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := fmt.Sprint(commands); got != "[D! 5 R 2 L! 4]" {
+        t.Errorf("commands = %s", got)
+    }
+
+    // With the puzzle's rules the knots fall behind after a jump, if they
+    // catch up the tail visits the cells in between.
+    eager := Rules{Slack: 1, Diagonal: true, CatchUp: 0}
+    tests := []struct {
+        rules Rules
+        knots int
+        tail geom.Point
+        visited int
+    }{
+        {DefaultRules, 2, geom.Pt(1, 4), 5},
+        {eager, 2, geom.Pt(-1, 5), 8},
+        {eager, 3, geom.Pt(0, 5), 6},
+    }
+    for _, tt := range tests {
+        rope := NewRope(tt.knots, tt.rules)
+        rope.Run(commands, nil)
+        if rope.Tail() != tt.tail || rope.Visited() != tt.visited {
+            t.Errorf("%d knots with %+v: tail at %v visited %d, want %v and %d",
+                tt.knots, tt.rules, rope.Tail(), rope.Visited(), tt.tail, tt.visited)
+        }
+    }
+
+    var out strings.Builder
+    s := Solver{Knots: 2, Frames: "-", Out: &out}
+    if _, err := s.Part2(strings.NewReader("D! 2\n")); err != nil {
+        t.Fatal(err)
+    }
+    if want := "== start ==\nH\n\n== D! 2 (2/2) ==\ns\n1\nH\n\n"; out.String() != want {
+        t.Errorf("frames =\n%s\nwant\n%s", out.String(), want)
+    }
+
+    for _, line := range []string{"X! 3\n", "D!! 3\n", "! 3\n"} {
+        if _, err := ReadCommands(strings.NewReader(line)); err == nil {
+            t.Errorf("ReadCommands(%q) didn't fail", line)
+        }
+    }
+}
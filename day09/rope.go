@@ -0,0 +1,170 @@
+package day09
+
+import (
+    "fmt"
+    "strings"
+
+    "aoc/geom"
+)
+
+// ----------------------------------- Rope -----------------------------------
+
+// Rope is a chain of knots. The head is moved by the commands, every other
+// knot follows the one before it. The rope remembers the positions visited
+// by its last knot.
+type Rope struct {
+    head *Head
+    tails []*Tail
+    visited map[geom.Point]bool
+    // visits holds the visited positions in the order of their first visit.
+    visits []geom.Point
+}
+
+// NewRope returns a rope of the given number of knots, including the head,
+// lying at the origin. Its knots follow the rules. It panics if there are
+// less than two knots.
+func NewRope(knots int, rules Rules) *Rope {
+    if knots < 2 {
+        panic(fmt.Sprintf("day09: rope of %d knots", knots))
+    }
+    r := &Rope{head: NewHead(0, 0), tails: make([]*Tail, knots-1), visited: make(map[geom.Point]bool)}
+    for i := range r.tails {
+        r.tails[i] = NewTail(0, 0)
+        r.tails[i].Rules = rules
+    }
+    r.visit(geom.Pt(0, 0))
+    return r
+}
+
+// Step moves the head one step in the given direction, pulling the other
+// knots along.
+func (r *Rope) Step(direction geom.Dir) {
+    r.head.Move(direction)
+    r.follow()
+}
+
+// Jump moves the head n cells in the given direction at once. Each knot
+// then follows the final position of the knot before it, only the steps of
+// the last knot count as visited.
+func (r *Rope) Jump(direction geom.Dir, n int) {
+    r.head.Jump(direction, n)
+    r.follow()
+}
+
+func (r *Rope) follow() {
+    var (
+        leader = r.head.Position()
+        path []geom.Point
+    )
+    for _, tail := range r.tails {
+        path = tail.MoveTo(leader)
+        leader = tail.Position()
+    }
+    for _, p := range path {
+        r.visit(p)
+    }
+}
+
+func (r *Rope) visit(p geom.Point) {
+    if !r.visited[p] {
+        r.visited[p] = true
+        r.visits = append(r.visits, p)
+    }
+}
+
+// Knots returns the positions of the knots starting with the head.
+func (r *Rope) Knots() []geom.Point {
+    knots := []geom.Point{r.head.Position()}
+    for _, tail := range r.tails {
+        knots = append(knots, tail.Position())
+    }
+    return knots
+}
+
+// Tail returns the position of the last knot.
+func (r *Rope) Tail() geom.Point {
+    return r.tails[len(r.tails)-1].Position()
+}
+
+// Visited returns the number of positions the last knot visited.
+func (r *Rope) Visited() int {
+    return len(r.visited)
+}
+
+// Bounds returns the smallest rectangle containing the knots and the
+// visited positions.
+func (r *Rope) Bounds() geom.Rect {
+    bounds := geom.Bounds(r.Knots()...)
+    for p := range r.visited {
+        bounds = bounds.Extend(p)
+    }
+    return bounds
+}
+
+// knotRune returns the label of the knot with the given index: H for the
+// head, then the digits and letters, * once they run out.
+func knotRune(i int) rune {
+    const labels = "H123456789abcdefghijklmnopqrstuvwxyz"
+    if i < len(labels) {
+        return rune(labels[i])
+    }
+    return '*'
+}
+
+// Frame draws the part of the plane within bounds like the puzzle does:
+// the knots by their labels, knots earlier in the rope covering later
+// ones, the start as s, other visited positions as # and the rest as dots.
+func (r *Rope) Frame(bounds geom.Rect) string {
+    var (
+        sb strings.Builder
+        knots = r.Knots()
+    )
+    for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+        for x := bounds.Min.X; x < bounds.Max.X; x++ {
+            p := geom.Pt(x, y)
+            c := '.'
+            switch {
+            case r.visited[p] && p == geom.Pt(0, 0):
+                c = 's'
+            case r.visited[p]:
+                c = '#'
+            }
+            for i := len(knots) - 1; i >= 0; i-- {
+                if knots[i] == p {
+                    c = knotRune(i)
+                }
+            }
+            sb.WriteRune(c)
+        }
+        sb.WriteRune('\n')
+    }
+    return sb.String()
+}
+
+func (r *Rope) String() string {
+    return r.Frame(r.Bounds())
+}
+
+// Run moves the rope by the commands. If observe isn't nil, it is called
+// before the first step and after every step with the command and the
+// number of its steps done, after a jump with all of them.
+func (r *Rope) Run(commands []*Command, observe func(r *Rope, cmd *Command, step int)) {
+    if observe != nil {
+        observe(r, nil, 0)
+    }
+    for _, cmd := range commands {
+        if cmd.Jump {
+            r.Jump(cmd.Direction, cmd.Steps)
+            if observe != nil {
+                observe(r, cmd, cmd.Steps)
+            }
+            continue
+        }
+        for i := 0; i < cmd.Steps; i++ {
+            r.Step(cmd.Direction)
+            if observe != nil {
+                observe(r, cmd, i+1)
+            }
+        }
+    }
+}
@@ -0,0 +1,60 @@
+package day09
+
+import (
+    "fmt"
+
+    "aoc/geom"
+)
+
+// ---------------------------------- Rules -----------------------------------
+
+// Rules describe how a knot follows the knot before it, its leader.
+type Rules struct {
+    // Slack is the Chebyshev distance the knot may lag behind its leader
+    // without moving.
+    Slack int
+    // Diagonal allows steps changing both coordinates. Without it the knot
+    // steps along the axis it is further away on, horizontally on ties.
+    Diagonal bool
+    // CatchUp is the most steps the knot takes when its leader moves, 0 for
+    // as many as it takes to be within Slack again.
+    CatchUp int
+}
+
+// DefaultRules are the rules of the puzzle: a knot touching its leader
+// stays, otherwise it takes a single step, diagonally if needed.
+var DefaultRules = Rules{Slack: 1, Diagonal: true, CatchUp: 1}
+
+func (r Rules) Validate() error {
+    switch {
+    case r.Slack < 0:
+        return fmt.Errorf("negative slack %d", r.Slack)
+    case r.CatchUp < 0:
+        return fmt.Errorf("negative catch-up %d", r.CatchUp)
+    }
+    return nil
+}
+
+// Follow returns the positions a knot at from steps on to follow its
+// leader, nil if it stays. Every step brings it closer, so the knot ends up
+// within Slack of the leader unless CatchUp stops it before.
+func (r Rules) Follow(from, leader geom.Point) []geom.Point {
+    var path []geom.Point
+    for geom.Chebyshev(from, leader) > r.Slack && (r.CatchUp == 0 || len(path) < r.CatchUp) {
+        from = from.Add(r.step(leader.Sub(from)))
+        path = append(path, from)
+    }
+    return path
+}
+
+// step returns the step towards the leader at the distance d.
+func (r Rules) step(d geom.Point) geom.Point {
+    switch {
+    case r.Diagonal:
+        return d.Sign()
+    case geom.Abs(d.X) >= geom.Abs(d.Y):
+        return geom.Pt(geom.Sign(d.X), 0)
+    default:
+        return geom.Pt(0, geom.Sign(d.Y))
+    }
+}
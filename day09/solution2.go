@@ -1,200 +1,96 @@
-package main
+package day09
 
 import (
     "bufio"
     "fmt"
-    "os"
-    "strings"
-    "strconv"
-    "math"
-)
-
-type Direction int
+    "io"
 
-const (
-    UP Direction = iota
-    DOWN
-    LEFT
-    RIGHT
+    "aoc/solver"
 )
 
-type Command struct {
-    Direction Direction
-    Steps int
-}
-
-func NewCommand(d Direction, steps int) *Command {
-    return &Command{d, steps}
-}
-
-func StringToDirection(s string) Direction {
-    switch s {
-    case "U":
-        return UP
-    case "D":
-        return DOWN
-    case "L":
-        return LEFT
-    case "R":
-        return RIGHT
-    default:
-        panic("unreachable line")
+func (s Solver) Part2(r io.Reader) (solver.Answer, error) {
+    commands, err := ReadCommands(r)
+    if err != nil {
+        return "", err
     }
-}
-
-type Position struct {
-    X, Y int
-}
-
-func (p Position) Difference(o Position) Position {
-    return Position{p.X - o.X, p.Y - o.Y}
-}
-
-type Head struct {
-    pos Position
-}
-
-func NewHead(x, y int) *Head {
-    return &Head{Position{x, y}}
-}
 
-func (h *Head) Move(direction Direction) {
-    switch direction {
-        case UP:
-            h.pos.Y += 1
-        case DOWN:
-            h.pos.Y -= 1
-        case LEFT:
-            h.pos.X -= 1
-        case RIGHT:
-            h.pos.X += 1
-        default:
-            panic("unreachable line")
+    var knots = s.Knots
+    if knots == 0 {
+        knots = DefaultKnots
     }
-}
-
-func (h *Head) Position() Position {
-    return h.pos
-}
-
-type Tail struct {
-    pos Position
-}
-
-func NewTail(x, y int) *Tail {
-    return &Tail{Position{x, y}}
-}
-
-func (t *Tail) Position() Position {
-    return t.pos
-}
-
-func (t *Tail) MoveTo(pos Position) {
-    var diff = t.pos.Difference(pos)
-
-    if diff.X == 0 && diff.Y == 0 {
-        return
+    rope, err := s.rope(knots)
+    if err != nil {
+        return "", err
     }
 
-    absX := math.Abs(float64(diff.X)) 
-    absY := math.Abs(float64(diff.Y))
-    if absX*absX + absY*absY <= 2 {
-        return
+    var (
+        frames *bufio.Writer
+        closeFrames = func() error { return nil }
+        anim *Animation
+    )
+    if s.Frames != "" {
+        w, close, err := s.create(s.Frames)
+        if err != nil {
+            return "", err
+        }
+        frames, closeFrames = bufio.NewWriter(w), close
+    }
+    if s.GIF != "" {
+        anim = &Animation{}
     }
 
-    // Assumption: the tail needs only one step (straigth, diagonally) 
-    //             to stay connected to the head.
-    
-    if (diff.X != 1 || diff.X != -1) && (diff.Y != 1 || diff.Y != -1) {
-        // need to move
-        if diff.X == 0 {
-            // move straight
-            if diff.Y > 0 {
-                // move up
-                t.pos.Y -= 1
-            } else {
-                // move down
-                t.pos.Y += 1
-            }
-        } else if diff.Y == 0 {
-            // move straight
-            if diff.X > 0 {
-                // move right
-                t.pos.X -= 1
+    // Frames are drawn within the bounds seen so far, so the picture only
+    // grows.
+    var bounds = rope.Bounds()
+    rope.Run(commands, func(rope *Rope, cmd *Command, step int) {
+        if frames != nil {
+            bounds = bounds.Union(rope.Bounds())
+            if cmd == nil {
+                fmt.Fprintf(frames, "== start ==\n")
             } else {
-                // move left
-                t.pos.X += 1
-            }
-        } else {
-            if diff.X > 0 && diff.Y > 0 {
-                // move top right
-                t.pos.X -= 1
-                t.pos.Y -= 1
-            } else if diff.X > 0 && diff.Y < 0 {
-                // move down right
-                t.pos.X -= 1
-                t.pos.Y += 1
-            } else if diff.X < 0 && diff.Y > 0 {
-                // move top left
-                t.pos.X += 1
-                t.pos.Y -= 1
-            } else if diff.X < 0 && diff.Y < 0 {
-                // move down left
-                t.pos.X += 1
-                t.pos.Y += 1
+                fmt.Fprintf(frames, "== %v (%d/%d) ==\n", cmd, step, cmd.Steps)
             }
+            fmt.Fprintf(frames, "%s\n", rope.Frame(bounds))
         }
-    }
-}
-
-func EachLineDo(f func(string)) error {
-    scanner := bufio.NewScanner(os.Stdin)
+        if anim != nil {
+            anim.Record(rope)
+        }
+    })
 
-    for scanner.Scan() {
-        line := scanner.Text()
-        f(line)
+    if frames != nil {
+        err := frames.Flush()
+        if cerr := closeFrames(); err == nil {
+            err = cerr
+        }
+        if err != nil {
+            return "", err
+        }
+    }
+    if anim != nil {
+        if err := s.writeGIF(anim); err != nil {
+            return "", err
+        }
     }
 
-    err := scanner.Err()
-
-    return err
+    return solver.Int(rope.Visited()), nil
 }
 
-func main() {
-    var commands = make([]*Command, 0)
-    err := EachLineDo(func(line string) {
-        split := strings.Split(line, " ")
-        direction := StringToDirection(split[0])
-        steps, _ := strconv.Atoi(split[1])
-        command := NewCommand(direction, steps)
-        commands = append(commands, command)
-    })
-
-    if err != nil {
-        fmt.Fprintf(os.Stderr, "reading stdin:", err)
-        os.Exit(1)
+func (s Solver) writeGIF(anim *Animation) error {
+    scale, delay := s.Scale, s.Delay
+    if scale < 1 {
+        scale = 1
     }
-
-    var (
-        head = NewHead(0, 0)
-        tails [9]*Tail
-        visited = make(map[Position]int)
-    )
-
-    for i := 0; i < len(tails); i++ {
-        tails[i] = NewTail(0, 0)
+    if delay < 0 {
+        delay = 0
     }
 
-    for _, cmd := range commands {
-        for i := 0; i < cmd.Steps; i++ {
-            head.Move(cmd.Direction)
-            tails[0].MoveTo(head.Position())
-            for i := 1; i < len(tails); i++ {
-                tails[i].MoveTo(tails[i-1].Position())
-            }
-            visited[tails[len(tails)-1].Position()] = 1
-        }
+    w, close, err := s.create(s.GIF)
+    if err != nil {
+        return err
     }
-
-    fmt.Println("Num. of visited positions by the tail:", len(visited))
+    err = anim.WriteGIF(w, scale, delay)
+    if cerr := close(); err == nil {
+        err = cerr
+    }
+    return err
 }
@@ -0,0 +1,319 @@
+package day10
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "io"
+    "sort"
+    "strconv"
+)
+
+// ---------------------------------- Binary ----------------------------------
+
+// MAGIC starts every encoded program.
+const MAGIC = "D10\x01"
+
+// Encode returns the binary form of a program:
+//
+//	MAGIC
+//	mnemonics     count, then length and bytes of each mnemonic used
+//	labels        count, then pc, length and bytes of each label name
+//	instructions  count, then the index of the mnemonic and the operands
+//
+// All numbers are varints, immediate operands signed, registers, label
+// operands and everything else unsigned. Opcodes are stored by name, so
+// programs survive changes to the instruction set. Only Ops can be encoded.
+func Encode(program []Instruction) ([]byte, error) {
+    var (
+        mnemonics []string
+        index = make(map[string]int)
+        labels []label
+        seen = make(map[label]bool)
+    )
+    for pc, ins := range program {
+        op, ok := ins.(Op)
+        if !ok {
+            return nil, fmt.Errorf("instruction %d: can't encode %T", pc, ins)
+        }
+        if _, ok := index[op.Opcode.Mnemonic]; !ok {
+            index[op.Opcode.Mnemonic] = len(mnemonics)
+            mnemonics = append(mnemonics, op.Opcode.Mnemonic)
+        }
+        for i, name := range op.Labels {
+            l := label{op.Args[i], name}
+            if op.Opcode.Operands[i] == Label && name != "" && !seen[l] {
+                seen[l] = true
+                labels = append(labels, l)
+            }
+        }
+    }
+
+    var buf = []byte(MAGIC)
+    appendString := func(s string) {
+        buf = binary.AppendUvarint(buf, uint64(len(s)))
+        buf = append(buf, s...)
+    }
+
+    buf = binary.AppendUvarint(buf, uint64(len(mnemonics)))
+    for _, mnemonic := range mnemonics {
+        appendString(mnemonic)
+    }
+    buf = binary.AppendUvarint(buf, uint64(len(labels)))
+    for _, l := range labels {
+        buf = binary.AppendUvarint(buf, uint64(l.pc))
+        appendString(l.name)
+    }
+    buf = binary.AppendUvarint(buf, uint64(len(program)))
+    for _, ins := range program {
+        op := ins.(Op)
+        buf = binary.AppendUvarint(buf, uint64(index[op.Opcode.Mnemonic]))
+        for i, operand := range op.Opcode.Operands {
+            if operand == Imm {
+                buf = binary.AppendVarint(buf, int64(op.Args[i]))
+            } else {
+                buf = binary.AppendUvarint(buf, uint64(op.Args[i]))
+            }
+        }
+    }
+    return buf, nil
+}
+
+// label is a name of the instruction with index pc.
+type label struct {
+    pc int
+    name string
+}
+
+// decoder reads the parts of an encoded program, remembering the first
+// error.
+type decoder struct {
+    data []byte
+    off int
+    err error
+}
+
+func (d *decoder) fail(format string, args ...any) {
+    if d.err == nil {
+        d.err = fmt.Errorf("offset %d: %s", d.off, fmt.Sprintf(format, args...))
+    }
+}
+
+func (d *decoder) uvarint(max int) int {
+    if d.err != nil {
+        return 0
+    }
+    n, size := binary.Uvarint(d.data[d.off:])
+    if size <= 0 {
+        d.fail("truncated or invalid number")
+        return 0
+    }
+    if n > uint64(max) {
+        d.fail("%d out of range 0..%d", n, max)
+        return 0
+    }
+    d.off += size
+    return int(n)
+}
+
+func (d *decoder) varint() int {
+    if d.err != nil {
+        return 0
+    }
+    n, size := binary.Varint(d.data[d.off:])
+    if size <= 0 || int64(int(n)) != n {
+        d.fail("truncated or invalid number")
+        return 0
+    }
+    d.off += size
+    return int(n)
+}
+
+func (d *decoder) string() string {
+    n := d.uvarint(len(d.data))
+    if d.err == nil && n > len(d.data)-d.off {
+        d.fail("truncated string")
+    }
+    if d.err != nil {
+        return ""
+    }
+    s := string(d.data[d.off : d.off+n])
+    d.off += n
+    return s
+}
+
+// Decode reads a program encoded by Encode.
+func Decode(data []byte) ([]Instruction, error) {
+    if !bytes.HasPrefix(data, []byte(MAGIC)) {
+        return nil, errors.New("not an encoded day10 program")
+    }
+    var d = &decoder{data: data, off: len(MAGIC)}
+
+    // Every entry takes at least a byte, which bounds the counts.
+    var opcodes = make([]*Opcode, d.uvarint(len(data)))
+    for i := range opcodes {
+        mnemonic := d.string()
+        if d.err != nil {
+            return nil, d.err
+        }
+        op, ok := LookupOpcode(mnemonic)
+        if !ok {
+            d.fail("unknown instruction %q", mnemonic)
+            return nil, d.err
+        }
+        opcodes[i] = op
+    }
+
+    var labels = make([]label, d.uvarint(len(data)))
+    for i := range labels {
+        labels[i].pc = d.uvarint(len(data))
+        if labels[i].name = d.string(); d.err == nil && !isName(labels[i].name) {
+            d.fail("invalid label %q", labels[i].name)
+        }
+    }
+
+    var program = make([]Instruction, d.uvarint(len(data)))
+    for pc := range program {
+        n := d.uvarint(len(opcodes) - 1)
+        if len(opcodes) == 0 {
+            d.fail("instructions without mnemonics")
+        }
+        if d.err != nil {
+            return nil, d.err
+        }
+        op := opcodes[n]
+        ins := Op{op, make([]int, len(op.Operands)), make([]string, len(op.Operands))}
+        for i, operand := range op.Operands {
+            switch operand {
+            case Imm:
+                ins.Args[i] = d.varint()
+            case Reg:
+                ins.Args[i] = d.uvarint(len(Registers) - 1)
+            case Label:
+                ins.Args[i] = d.uvarint(len(program))
+            }
+        }
+        program[pc] = ins
+    }
+    if d.err != nil {
+        return nil, d.err
+    }
+    if d.off != len(data) {
+        d.fail("%d bytes after the program", len(data)-d.off)
+        return nil, d.err
+    }
+
+    // Label operands show the first name of their target.
+    var names = make(map[int]string)
+    for _, l := range labels {
+        if l.pc > len(program) {
+            return nil, fmt.Errorf("label %s at %d after the end of the program", l.name, l.pc)
+        }
+        if _, ok := names[l.pc]; !ok {
+            names[l.pc] = l.name
+        }
+    }
+    for _, ins := range program {
+        op := ins.(Op)
+        for i, operand := range op.Opcode.Operands {
+            if operand == Label {
+                op.Labels[i] = names[op.Args[i]]
+            }
+        }
+    }
+    return program, nil
+}
+
+// LoadProgram reads a program either encoded by Encode or as text, see
+// ReadProgram.
+func LoadProgram(r io.Reader) ([]Instruction, error) {
+    br := bufio.NewReader(r)
+    if magic, _ := br.Peek(len(MAGIC)); string(magic) != MAGIC {
+        return ReadProgram(br)
+    }
+    data, err := io.ReadAll(br)
+    if err != nil {
+        return nil, err
+    }
+    return Decode(data)
+}
+
+// Disassemble writes a program as text ReadProgram reads back. Label
+// operands without a name, or with a name already given to another
+// instruction, get one like "l12".
+func Disassemble(w io.Writer, program []Instruction) error {
+    var (
+        names = make(map[int][]string)
+        taken = make(map[string]bool)
+        // pcs maps the names of label operands to their targets.
+        pcs = make(map[string]int)
+    )
+    for _, ins := range program {
+        if op, ok := ins.(Op); ok {
+            for i, name := range op.Labels {
+                if op.Opcode.Operands[i] == Label && name != "" && !taken[name] {
+                    taken[name] = true
+                    pcs[name] = op.Args[i]
+                    names[op.Args[i]] = append(names[op.Args[i]], name)
+                }
+            }
+        }
+    }
+
+    // The names used by operands, generating missing ones.
+    operandName := func(pc int) string {
+        if len(names[pc]) > 0 {
+            return names[pc][0]
+        }
+        name := "l" + strconv.Itoa(pc)
+        for taken[name] {
+            name += "_"
+        }
+        taken[name] = true
+        names[pc] = []string{name}
+        return name
+    }
+
+    var lines = make([]string, len(program))
+    for pc, ins := range program {
+        op, ok := ins.(Op)
+        if !ok {
+            lines[pc] = ins.String()
+            continue
+        }
+        named := Op{op.Opcode, op.Args, make([]string, len(op.Labels))}
+        for i, operand := range op.Opcode.Operands {
+            if operand == Label {
+                named.Labels[i] = op.Labels[i]
+                if pc, ok := pcs[named.Labels[i]]; !ok || pc != op.Args[i] {
+                    named.Labels[i] = operandName(op.Args[i])
+                }
+            }
+        }
+        lines[pc] = named.String()
+    }
+
+    bw := bufio.NewWriter(w)
+    for pc := 0; pc <= len(program); pc++ {
+        labels := names[pc]
+        sort.Strings(labels)
+        for _, name := range labels {
+            fmt.Fprintf(bw, "%s:\n", name)
+        }
+        if pc < len(program) {
+            fmt.Fprintln(bw, lines[pc])
+        }
+    }
+    return bw.Flush()
+}
+
+// Assemble reads a program as text and returns its binary form.
+func Assemble(r io.Reader) ([]byte, error) {
+    program, err := ReadProgram(r)
+    if err != nil {
+        return nil, err
+    }
+    return Encode(program)
+}
@@ -0,0 +1,279 @@
+// Package day10 solves "Cathode-Ray Tube".
+package day10
+
+import (
+    "flag"
+    "fmt"
+    "io"
+    "strings"
+
+    "aoc/input"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(10, &Solver{MaxCycles: DefaultMaxCycles, TraceFormat: "csv", Screen: DefaultScreen, RenderFormat: "text"})
+}
+
+// DefaultMaxCycles is the number of cycles part 1 runs at most.
+const DefaultMaxCycles = 1000000
+
+// Solver sums up the signal strengths of the cycles selected by Query in
+// part 1, of the Checkpoints if the query is empty. Part 1 fails if the
+// program runs longer than MaxCycles, DefaultMaxCycles if not positive. If
+// Trace is set, part 1 writes the trace of every cycle to the file named by
+// it, "-" for Out, in TraceFormat, see TraceFormats.
+//
+// Part 2 is answered with the letters read from the screen, or the screen
+// itself if Draw is set. If Render is set, the screen is also written to the
+// file named by it, "-" for Out, in RenderFormat, see Renderers. Part 2 runs
+// in the Debugger if Debug is set, reading the commands from In and writing
+// to Out, os.Stdin and os.Stdout if nil.
+type Solver struct {
+    Query Query
+    MaxCycles int
+    Trace string
+    TraceFormat string
+    Screen ScreenOptions
+    Render string
+    RenderFormat string
+    Draw bool
+    Debug bool
+    In io.Reader
+    Out io.Writer
+}
+
+func (s *Solver) Flags(fs *flag.FlagSet) {
+    fs.Func("cycles", "comma separated cycles whose signal strengths part 1 sums up", func(value string) error {
+        cycles, err := parseCycles(value)
+        s.Query.Cycles = cycles
+        return err
+    })
+    fs.IntVar(&s.Query.Every, "every", s.Query.Every, "sum up the signal strengths of every `n`th cycle in part 1")
+    fs.IntVar(&s.MaxCycles, "max-cycles", s.MaxCycles, "stop part 1 with an error after `n` cycles")
+    fs.StringVar(&s.Trace, "trace", s.Trace, "write the trace of part 1 to `file`, - for stdout")
+    fs.StringVar(&s.TraceFormat, "trace-format", s.TraceFormat, "format of the trace: "+strings.Join(TraceFormatNames(), ", "))
+    fs.IntVar(&s.Screen.Width, "width", s.Screen.Width, "width of the screen in pixels")
+    fs.IntVar(&s.Screen.Height, "height", s.Screen.Height, "height of the screen in pixels")
+    fs.IntVar(&s.Screen.SpriteWidth, "sprite", s.Screen.SpriteWidth, "width of the sprite in pixels")
+    fs.Func("lit", "`glyph` of lit pixels (default "+string(s.Screen.Lit)+")", glyphFlag(&s.Screen.Lit))
+    fs.Func("dark", "`glyph` of dark pixels (default "+string(s.Screen.Dark)+")", glyphFlag(&s.Screen.Dark))
+    fs.StringVar(&s.Render, "render", s.Render, "write the screen of part 2 to `file`, - for stdout")
+    fs.StringVar(&s.RenderFormat, "render-format", s.RenderFormat, "format of the rendered screen: "+strings.Join(RendererNames(), ", "))
+    fs.BoolVar(&s.Draw, "draw", s.Draw, "answer part 2 with the screen instead of the letters read from it")
+    fs.BoolVar(&s.Debug, "debug", s.Debug, "debug the program of part 2 interactively")
+}
+
+// glyphFlag parses the value of a flag holding a single character.
+func glyphFlag(glyph *rune) func(string) error {
+    return func(value string) error {
+        runes := []rune(value)
+        if len(runes) != 1 {
+            return fmt.Errorf("%q isn't a single character", value)
+        }
+        *glyph = runes[0]
+        return nil
+    }
+}
+
+// ----------------------------------- CPU ------------------------------------
+
+// Registers are the names of the registers of the CPU in the order of
+// their indices.
+var Registers = []string{"x", "y"}
+
+type Cpu struct {
+    X, Y int
+    pc int
+    program []Instruction
+    delay int
+}
+
+func NewCpu(program []Instruction) *Cpu {
+    return &Cpu{X: 1, pc: 0, program: program, delay: 0}
+}
+
+// Reg returns the register with the given index.
+func (cpu *Cpu) Reg(i int) *int {
+    switch i {
+    case 0:
+        return &cpu.X
+    case 1:
+        return &cpu.Y
+    default:
+        panic(fmt.Sprintf("day10: no register %d", i))
+    }
+}
+
+// PC returns the index of the current instruction.
+func (cpu *Cpu) PC() int {
+    return cpu.pc
+}
+
+// Jump continues the program at the instruction with index pc.
+func (cpu *Cpu) Jump(pc int) {
+    cpu.pc = pc
+}
+
+// Current returns the instruction being executed, or if none is, the one
+// executed next. It returns nil when the program has ended.
+func (cpu *Cpu) Current() Instruction {
+    if cpu.Ready() {
+        return nil
+    }
+    return cpu.program[cpu.pc]
+}
+
+// Delay returns the number of cycles left until the current instruction
+// takes effect, 0 if no instruction is being executed.
+func (cpu *Cpu) Delay() int {
+    return cpu.delay
+}
+
+func (cpu *Cpu) Fetch() {
+    cpu.delay = cpu.program[cpu.pc].Cycles()
+}
+
+func (cpu *Cpu) Exec() {
+    cpu.delay--
+
+    if cpu.delay > 0 {
+        return
+    }
+
+    var ins = cpu.program[cpu.pc]
+    cpu.pc++
+    ins.Exec(cpu)
+}
+
+// Tick runs a single cycle, fetching the next instruction if none is being
+// executed.
+func (cpu *Cpu) Tick() {
+    if !cpu.Processing() {
+        cpu.Fetch()
+    }
+    cpu.Exec()
+}
+
+func (cpu *Cpu) Processing() bool {
+    return cpu.delay > 0
+}
+
+func (cpu *Cpu) Ready() bool {
+    return cpu.pc < 0 || cpu.pc >= len(cpu.program)
+}
+
+// --------------------------------- Program ----------------------------------
+
+// ReadProgram reads one instruction per line, see Mnemonics for the
+// instruction set. A line like "loop:" defines a label for the instruction
+// following it, empty lines are skipped.
+func ReadProgram(r io.Reader) ([]Instruction, error) {
+    var (
+        program []Instruction
+        labels = make(map[string]int)
+        // The label operands to resolve after reading all lines.
+        fixups []fixup
+        line int
+    )
+
+    err := input.EachLine(r, func(text string) error {
+        line++
+        split := input.Fields(text)
+        if len(split) == 0 {
+            return nil
+        }
+
+        if name, ok := strings.CutSuffix(split[0].Text, ":"); ok {
+            if len(split) != 1 || !isName(name) {
+                return input.Errorf("expected \"<label>:\"")
+            }
+            if _, dup := labels[name]; dup {
+                return split[0].Errorf("label %s defined twice", name)
+            }
+            labels[name] = len(program)
+            return nil
+        }
+
+        op, ok := LookupOpcode(split[0].Text)
+        if !ok {
+            return split[0].Errorf("unknown instruction")
+        }
+        if n := len(op.Operands); len(split)-1 > n {
+            return split[n+1].Errorf("unexpected operand")
+        } else if len(split)-1 < n {
+            return input.Errorf("expected %q", op.Usage())
+        }
+
+        ins := Op{op, make([]int, len(op.Operands)), make([]string, len(op.Operands))}
+        for i, operand := range op.Operands {
+            arg := split[i+1]
+            switch operand {
+            case Imm:
+                n, err := arg.Int()
+                if err != nil {
+                    return err
+                }
+                ins.Args[i] = n
+            case Reg:
+                reg := registerIndex(arg.Text)
+                if reg < 0 {
+                    return arg.Errorf("unknown register")
+                }
+                ins.Args[i] = reg
+            case Label:
+                if !isName(arg.Text) {
+                    return arg.Errorf("invalid label")
+                }
+                ins.Labels[i] = arg.Text
+                fixups = append(fixups, fixup{len(program), i, line, arg})
+            }
+        }
+        program = append(program, ins)
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    for _, f := range fixups {
+        pc, ok := labels[f.arg.Text]
+        if !ok {
+            pe := f.arg.Errorf("undefined label")
+            pe.Line = f.line
+            return nil, pe
+        }
+        program[f.pc].(Op).Args[f.operand] = pc
+    }
+
+    return program, nil
+}
+
+// fixup is a label operand of an instruction.
+type fixup struct {
+    pc, operand int
+    line int
+    arg input.Field
+}
+
+func registerIndex(name string) int {
+    for i, reg := range Registers {
+        if reg == name {
+            return i
+        }
+    }
+    return -1
+}
+
+func isName(s string) bool {
+    if s == "" {
+        return false
+    }
+    for i, c := range s {
+        letter := 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_'
+        if !letter && (i == 0 || c < '0' || c > '9') {
+            return false
+        }
+    }
+    return true
+}
@@ -0,0 +1,705 @@
+package day10
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "image/color"
+    "image/png"
+    "io"
+    "math/rand"
+    "reflect"
+    "strings"
+    "testing"
+    "testing/quick"
+
+    "aoc/input"
+    "aoc/solver"
+    "aoc/solver/solvertest"
+)
+
+const example = `addx 15
+addx -11
+addx 6
+addx -3
+addx 5
+addx -1
+addx -8
+addx 13
+addx 4
+noop
+addx -1
+addx 5
+addx -1
+addx 5
+addx -1
+addx 5
+addx -1
+addx 5
+addx -1
+addx -35
+addx 1
+addx 24
+addx -19
+addx 1
+addx 16
+addx -11
+noop
+noop
+addx 21
+addx -15
+noop
+noop
+addx -3
+addx 9
+addx 1
+addx -3
+addx 8
+addx 1
+addx 5
+noop
+noop
+noop
+noop
+noop
+addx -36
+noop
+addx 1
+addx 7
+noop
+noop
+noop
+addx 2
+addx 6
+noop
+noop
+noop
+noop
+noop
+addx 1
+noop
+noop
+addx 7
+addx 1
+noop
+addx -13
+addx 13
+addx 7
+noop
+addx 1
+addx -33
+noop
+noop
+noop
+addx 2
+noop
+noop
+noop
+addx 8
+noop
+addx -1
+addx 2
+addx 1
+noop
+addx 17
+addx -9
+addx 1
+addx 1
+addx -3
+addx 11
+noop
+noop
+addx 1
+noop
+addx 1
+noop
+noop
+addx -13
+addx -19
+addx 1
+addx 3
+addx 26
+addx -30
+addx 12
+addx -1
+addx 3
+addx 1
+noop
+noop
+noop
+addx -9
+addx 18
+addx 1
+addx 2
+noop
+noop
+addx 9
+noop
+noop
+noop
+addx -1
+addx 2
+addx -37
+addx 1
+addx 3
+noop
+addx 15
+addx -21
+addx 22
+addx -6
+addx 1
+noop
+addx 2
+addx 1
+noop
+addx -10
+noop
+noop
+addx 20
+addx 1
+addx 2
+addx 2
+addx -6
+addx -11
+noop
+noop
+noop
+`
+
+const exampleScreen = `##..##..##..##..##..##..##..##..##..##..
+###...###...###...###...###...###...###.
+####....####....####....####....####....
+#####.....#####.....#####.....#####.....
+######......######......######......####
+#######.......#######.......#######.....
+`
+
+func TestExamples(t *testing.T) {
+    solvertest.Run(t, Solver{Draw: true}, []solvertest.Example{
+        {Name: "example", Input: example, Part1: "13140", Part2: exampleScreen},
+    })
+}
+
+func TestCpu(t *testing.T) {
+    program, err := ReadProgram(strings.NewReader("noop\naddx 3\naddx -5\n"))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // value of X during each cycle
+    want := []int{1, 1, 1, 4, 4}
+
+    var cpu = NewCpu(program)
+    for cycle := 0; !cpu.Ready(); cycle++ {
+        if cycle >= len(want) {
+            t.Fatalf("program still running after %d cycles", cycle)
+        }
+        if cpu.X != want[cycle] {
+            t.Errorf("cycle %d: X = %d, want %d", cycle+1, cpu.X, want[cycle])
+        }
+        if !cpu.Processing() {
+            cpu.Fetch()
+        }
+        cpu.Exec()
+    }
+
+    if cpu.X != -1 {
+        t.Errorf("X = %d after program, want -1", cpu.X)
+    }
+}
+
+func TestMalformedInput(t *testing.T) {
+    tests := []struct {
+        input string
+        line, column int
+    }{
+        {"noop\naddx\n", 2, 0},
+        {"noop\naddx 1x\n", 2, 6},
+        {"noop\nnoop 3\n", 2, 6},
+        {"noop\nnoop\nsubx 3\n", 3, 1},
+        {"mul z 3\n", 1, 5},
+        {"noop\njmp end\n", 2, 5},
+        {"a:\nnoop\na:\n", 3, 1},
+        {"jnz x 1\n", 1, 7},
+        {"addx 1 2\n", 1, 8},
+    }
+
+    for _, tt := range tests {
+        _, err := Solver{}.Part1(strings.NewReader(tt.input))
+        var pe *input.ParseError
+        if !errors.As(err, &pe) || pe.Line != tt.line || pe.Column != tt.column {
+            t.Errorf("Part1(%q) error = %v, want parse error at %d:%d", tt.input, err, tt.line, tt.column)
+        }
+    }
+}
+
+func TestExtendedInstructions(t *testing.T) {
+    program, err := ReadProgram(strings.NewReader(`addy 3
+loop:
+addx 2
+addy -1
+
+jnz y loop
+mul x 2
+`))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var cpu = NewCpu(program)
+    cycles := 0
+    for ; !cpu.Ready(); cycles++ {
+        cpu.Tick()
+    }
+
+    // addy, three times addx, addy and jnz and finally mul
+    if want := 2 + 3*(2+2+1) + 3; cycles != want {
+        t.Errorf("program took %d cycles, want %d", cycles, want)
+    }
+    if cpu.X != 14 || cpu.Y != 0 {
+        t.Errorf("X, Y = %d, %d, want 14, 0", cpu.X, cpu.Y)
+    }
+    if got := program[3].String(); got != "jnz y loop" {
+        t.Errorf("String() = %q, want %q", got, "jnz y loop")
+    }
+}
+
+func TestDefine(t *testing.T) {
+    if _, ok := LookupOpcode("decx"); !ok {
+        Define(&Opcode{Mnemonic: "decx", Cycles: 4, Operands: []Operand{Imm}, Exec: func(cpu *Cpu, args []int) {
+            cpu.X -= args[0]
+        }})
+    }
+
+    program, err := ReadProgram(strings.NewReader("decx 3\n"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    var cpu = NewCpu(program)
+    for cycle := 1; cycle <= 4; cycle++ {
+        if cpu.X != 1 {
+            t.Errorf("cycle %d: X = %d, want 1", cycle, cpu.X)
+        }
+        cpu.Tick()
+    }
+    if !cpu.Ready() || cpu.X != -2 {
+        t.Errorf("after 4 cycles: ready %t, X = %d, want ready and -2", cpu.Ready(), cpu.X)
+    }
+
+    defer func() {
+        if recover() == nil {
+            t.Error("defining decx twice didn't panic")
+        }
+    }()
+    Define(&Opcode{Mnemonic: "decx", Cycles: 1})
+}
+
+func TestDebugger(t *testing.T) {
+    var out strings.Builder
+    s := Solver{
+        Draw: true,
+        Debug: true,
+        In: strings.NewReader("break cycle 20\ncontinue\nwatch X\nstep 2\ncontinue\nbreak pc 1000\nbreak\nfly\nquit\n"),
+        Out: &out,
+    }
+    got, err := s.Part2(strings.NewReader(example))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got != exampleScreen {
+        t.Errorf("Part2 after debugging =\n%s", got)
+    }
+
+    // During the 20th cycle X is 21, the puzzle's first signal strength
+    // checkpoint.
+    for _, want := range []string{
+        "breakpoint at cycle 20\ncycle 20, pc 10: addx -1 (2 of 2 cycles left)\nX = 21, Y = 0\n##..##..##..##..##.                     \n",
+        "x changed from 21 to 20\ncycle 22, pc 11: addx 5 (2 of 2 cycles left)\n",
+        "x changed from 20 to 25\ncycle 24, pc 12",
+        "break cycle 20\nbreak pc 1000\nwatch x\n",
+        "error: unknown command \"fly\"",
+    } {
+        if !strings.Contains(out.String(), want) {
+            t.Errorf("debugger output lacks %q:\n%s", want, out.String())
+        }
+    }
+}
+
+// screenOf returns a screen showing the given rows.
+func screenOf(t *testing.T, rows string) *Screen {
+    screen := NewScreen(DefaultScreen)
+    for _, c := range strings.ReplaceAll(rows, "\n", "") {
+        screen.SetSprite(-10)
+        if c == '#' {
+            screen.SetSprite(screen.col)
+        }
+        screen.DrawPixel()
+    }
+    if !screen.Full() {
+        t.Fatalf("screen not full after drawing\n%s", rows)
+    }
+    return screen
+}
+
+func TestDecode(t *testing.T) {
+    letters := `###..###....##.#....####.#..#.#....###..
+#..#.#..#....#.#....#....#..#.#....#..#.
+###..#..#....#.#....###..#..#.#....#..#.
+#..#.###.....#.#....#....#..#.#....###..
+#..#.#.#..#..#.#....#....#..#.#....#....
+###..#..#..##..####.#.....##..####.#....
+`
+    got, err := screenOf(t, letters).Decode()
+    if got != "BRJLFULP" || err != nil {
+        t.Errorf("Decode() = %q, %v, want BRJLFULP", got, err)
+    }
+
+    // The first glyph of the example isn't a letter, neither is the last
+    // one, whose spacing column is lit.
+    got, err = screenOf(t, exampleScreen).Decode()
+    if err == nil || !strings.HasPrefix(err.Error(), "unrecognised glyphs in \"????????\":\nglyph 1:\n##..\n###.\n####\n####\n####\n####\nglyph 2:") {
+        t.Errorf("Decode() of the example = %q, %v", got, err)
+    }
+}
+
+func TestFont(t *testing.T) {
+    for g, letter := range Font {
+        rows := strings.Split(g, "\n")
+        if len(rows) != GLYPH_HEIGHT {
+            t.Errorf("%c has %d rows", letter, len(rows))
+        }
+        for _, row := range rows {
+            if len(row) != GLYPH_WIDTH {
+                t.Errorf("%c has row %q", letter, row)
+            }
+        }
+    }
+
+    // Every letter is read back from a screen showing it.
+    for g, letter := range Font {
+        var screen strings.Builder
+        for _, row := range strings.Split(g, "\n") {
+            screen.WriteString(strings.Repeat(row+".", 8) + "\n")
+        }
+        got, err := screenOf(t, screen.String()).Decode()
+        if want := strings.Repeat(string(letter), 8); got != want || err != nil {
+            t.Errorf("Decode() of %c = %q, %v", letter, got, err)
+        }
+    }
+}
+
+func TestTrace(t *testing.T) {
+    const program = "noop\naddx 3\naddx -5\n"
+    tests := []struct {
+        format string
+        want string
+    }{
+        {"csv", `cycle,pc,instruction,x_before,x_after,pixel
+1,0,noop,1,1,#
+2,1,addx 3,1,1,#
+3,1,addx 3,1,4,#
+4,2,addx -5,4,4,#
+5,2,addx -5,4,-1,#
+`},
+        {"jsonl", `{"cycle":1,"pc":0,"instruction":"noop","x_before":1,"x_after":1,"pixel":"#"}
+{"cycle":2,"pc":1,"instruction":"addx 3","x_before":1,"x_after":1,"pixel":"#"}
+{"cycle":3,"pc":1,"instruction":"addx 3","x_before":1,"x_after":4,"pixel":"#"}
+{"cycle":4,"pc":2,"instruction":"addx -5","x_before":4,"x_after":4,"pixel":"#"}
+{"cycle":5,"pc":2,"instruction":"addx -5","x_before":4,"x_after":-1,"pixel":"#"}
+`},
+    }
+
+    for _, tt := range tests {
+        var out strings.Builder
+        s := Solver{Query: Query{Every: 2}, Trace: "-", TraceFormat: tt.format, Out: &out}
+        got, err := s.Part1(strings.NewReader(program))
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got != "18" {
+            t.Errorf("%s: Part1 = %s, want 18", tt.format, got)
+        }
+        if out.String() != tt.want {
+            t.Errorf("%s: trace =\n%s\nwant\n%s", tt.format, out.String(), tt.want)
+        }
+    }
+
+    s := Solver{Trace: "-", TraceFormat: "xml", Out: io.Discard}
+    if _, err := s.Part1(strings.NewReader(program)); err == nil {
+        t.Error("Part1 with unknown trace format didn't fail")
+    }
+}
+
+func TestMaxCycles(t *testing.T) {
+    const loop = "loop:\naddx 1\njmp loop\n"
+    for _, s := range []Solver{
+        {Query: Query{Every: 20}},
+        {Query: Query{Every: 20}, MaxCycles: 100},
+        {Trace: "-", Out: io.Discard, MaxCycles: 100},
+        {Query: Query{Cycles: []int{500}}, MaxCycles: 100},
+    } {
+        want := s.MaxCycles
+        if want == 0 {
+            want = DefaultMaxCycles
+        }
+        _, err := s.Part1(strings.NewReader(loop))
+        if err == nil || err.Error() != fmt.Sprintf("program still running after %d cycles", want) {
+            t.Errorf("Part1 of an endless loop with %+v error = %v", s, err)
+        }
+    }
+
+    // Programs ending in time aren't affected.
+    got, err := Solver{Query: Query{Every: 20}, MaxCycles: 240}.Part1(strings.NewReader(example))
+    if err != nil || got != "31140" {
+        t.Errorf("Part1 of the example = %s, %v", got, err)
+    }
+}
+
+func TestQuery(t *testing.T) {
+    tests := []struct {
+        query Query
+        want solver.Answer
+    }{
+        {Query{}, "13140"},
+        {Query{Cycles: Checkpoints}, "13140"},
+        {Query{Cycles: []int{20}}, "420"},
+        {Query{Cycles: []int{220, 20, 1000}}, "4380"},
+        {Query{Every: 20}, "31140"},
+        {Query{Every: 40}, "18000"},
+    }
+
+    for _, tt := range tests {
+        got, err := Solver{Query: tt.query}.Part1(strings.NewReader(example))
+        if err != nil || got != tt.want {
+            t.Errorf("Part1 with %+v = %s, %v, want %s", tt.query, got, err, tt.want)
+        }
+    }
+
+    cycles, err := parseCycles("20, 60,100")
+    if err != nil || len(cycles) != 3 || cycles[1] != 60 {
+        t.Errorf("parseCycles = %v, %v", cycles, err)
+    }
+    for _, value := range []string{"", "20,,60", "0", "x"} {
+        if _, err := parseCycles(value); err == nil {
+            t.Errorf("parseCycles(%q) didn't fail", value)
+        }
+    }
+}
+
+func TestScreenOptions(t *testing.T) {
+    // X stays 1, so the sprite covers the columns around column 1.
+    program := strings.Repeat("noop\n", 12)
+    tests := []struct {
+        opts ScreenOptions
+        want string
+    }{
+        {ScreenOptions{Width: 6, Height: 2}, "###...\n###...\n"},
+        {ScreenOptions{Width: 4, Height: 3, SpriteWidth: 1}, ".#..\n.#..\n.#..\n"},
+        {ScreenOptions{Width: 6, Height: 2, SpriteWidth: 4}, "####..\n####..\n"},
+        {ScreenOptions{Width: 6, Height: 2, SpriteWidth: 5, Lit: '█', Dark: ' '}, "████  \n████  \n"},
+        {ScreenOptions{Width: 5, Height: 3}, "###..\n###..\n##   \n"},
+    }
+
+    for _, tt := range tests {
+        got, err := Solver{Screen: tt.opts, Draw: true}.Part2(strings.NewReader(program))
+        if err != nil || got != solver.Answer(tt.want) {
+            t.Errorf("Part2 with %+v = %v\n%s, want\n%s", tt.opts, err, got, tt.want)
+        }
+    }
+
+    for _, opts := range []ScreenOptions{
+        {Width: -1},
+        {SpriteWidth: -3},
+        {Lit: '.'},
+    } {
+        if _, err := (Solver{Screen: opts}).Part2(strings.NewReader(program)); err == nil {
+            t.Errorf("Part2 with %+v didn't fail", opts)
+        }
+    }
+}
+
+func TestRender(t *testing.T) {
+    screen := screenOf(t, exampleScreen)
+
+    var text strings.Builder
+    if err := RenderText(&text, screen); err != nil || text.String() != exampleScreen {
+        t.Errorf("RenderText = %v\n%s", err, text.String())
+    }
+
+    var ansi strings.Builder
+    if err := RenderANSI(&ansi, screen); err != nil {
+        t.Fatal(err)
+    }
+    lit, dark := "\x1b[48;2;102;255;102m  ", "\x1b[48;2;16;48;16m  "
+    if want := lit + lit + dark + dark + lit; !strings.HasPrefix(ansi.String(), want) {
+        t.Errorf("RenderANSI starts with %q, want %q", ansi.String()[:len(want)], want)
+    }
+    if got := strings.Count(ansi.String(), "\x1b[0m\n"); got != 6 {
+        t.Errorf("RenderANSI wrote %d rows, want 6", got)
+    }
+
+    var buf bytes.Buffer
+    if err := RenderPNG(&buf, screen); err != nil {
+        t.Fatal(err)
+    }
+    img, err := png.Decode(&buf)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if b := img.Bounds(); b.Dx() != 40*PIXEL_SIZE || b.Dy() != 6*PIXEL_SIZE {
+        t.Errorf("PNG is %dx%d", b.Dx(), b.Dy())
+    }
+    for _, p := range []struct {
+        x, y int
+        want color.RGBA
+    }{
+        {0, 0, LitColor},
+        {2*PIXEL_SIZE + 3, PIXEL_SIZE - 1, DarkColor},
+        {39*PIXEL_SIZE, 5*PIXEL_SIZE, DarkColor},
+    } {
+        if got := color.RGBAModel.Convert(img.At(p.x, p.y)); got != p.want {
+            t.Errorf("PNG pixel %d,%d = %v, want %v", p.x, p.y, got, p.want)
+        }
+    }
+
+    // The example doesn't draw letters, so Part2 fails after rendering.
+    var out strings.Builder
+    s := Solver{Render: "-", RenderFormat: "ansi", Out: &out}
+    if _, err := s.Part2(strings.NewReader(example)); err == nil || out.String() == "" {
+        t.Errorf("Part2 rendering the example = %v, wrote %d bytes", err, out.Len())
+    }
+    s.RenderFormat = "svg"
+    if _, err := s.Part2(strings.NewReader(example)); err == nil || !strings.Contains(err.Error(), "svg") {
+        t.Errorf("Part2 with unknown render format error = %v", err)
+    }
+}
+
+// randomProgram generates programs of the opcodes of the puzzle. Operands
+// targeting the same instruction share its label name, if it has one.
+type randomProgram []Instruction
+
+func (randomProgram) Generate(rnd *rand.Rand, size int) reflect.Value {
+    mnemonics := []string{"noop", "addx", "addy", "mul", "jmp", "jnz"}
+    n := rnd.Intn(size + 1)
+    names := make([]string, n+1)
+    for pc := range names {
+        if rnd.Intn(2) == 0 {
+            names[pc] = fmt.Sprintf("label%d", pc)
+        }
+    }
+
+    program := make(randomProgram, n)
+    for pc := range program {
+        op, _ := LookupOpcode(mnemonics[rnd.Intn(len(mnemonics))])
+        ins := Op{op, make([]int, len(op.Operands)), make([]string, len(op.Operands))}
+        for i, operand := range op.Operands {
+            switch operand {
+            case Imm:
+                ins.Args[i] = int(rnd.Int63()) >> rnd.Intn(64)
+                if rnd.Intn(2) == 0 {
+                    ins.Args[i] = -ins.Args[i]
+                }
+            case Reg:
+                ins.Args[i] = rnd.Intn(len(Registers))
+            case Label:
+                ins.Args[i] = rnd.Intn(n + 1)
+                ins.Labels[i] = names[ins.Args[i]]
+            }
+        }
+        program[pc] = ins
+    }
+    return reflect.ValueOf(program)
+}
+
+func sameProgram(a, b []Instruction, labels bool) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for pc := range a {
+        x, y := a[pc].(Op), b[pc].(Op)
+        if x.Opcode != y.Opcode || !reflect.DeepEqual(x.Args, y.Args) || labels && !reflect.DeepEqual(x.Labels, y.Labels) {
+            return false
+        }
+    }
+    return true
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+    err := quick.Check(func(program randomProgram) bool {
+        data, err := Encode(program)
+        if err != nil {
+            t.Log(err)
+            return false
+        }
+        decoded, err := Decode(data)
+        if err != nil || !sameProgram(program, decoded, true) {
+            t.Log(err)
+            return false
+        }
+
+        // Unnamed labels get new names in the text, so only the
+        // instructions survive.
+        var text strings.Builder
+        if err := Disassemble(&text, decoded); err != nil {
+            return false
+        }
+        parsed, err := ReadProgram(strings.NewReader(text.String()))
+        if err != nil || !sameProgram(program, parsed, false) {
+            t.Logf("%v\n%s", err, text.String())
+            return false
+        }
+        return true
+    }, &quick.Config{MaxCount: 500})
+    if err != nil {
+        t.Error(err)
+    }
+}
+
+func TestBinary(t *testing.T) {
+    data, err := Assemble(strings.NewReader(example))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(data) >= len(example)/2 {
+        t.Errorf("encoded example takes %d bytes, the text %d", len(data), len(example))
+    }
+    solvertest.Run(t, Solver{Draw: true}, []solvertest.Example{
+        {Name: "binary", Input: string(data), Part1: "13140", Part2: exampleScreen},
+    })
+
+    const source = "start:\naddy 3\nloop:\naddx -2\naddy -1\njnz y loop\njmp end\nmul x 2\nend:\n"
+    data, err = Assemble(strings.NewReader(source))
+    if err != nil {
+        t.Fatal(err)
+    }
+    var text strings.Builder
+    program, err := LoadProgram(bytes.NewReader(data))
+    if err == nil {
+        err = Disassemble(&text, program)
+    }
+    if want := strings.Replace(source, "start:\n", "", 1); err != nil || text.String() != want {
+        t.Errorf("disassembly = %v\n%s, want\n%s", err, text.String(), want)
+    }
+
+    // Every prefix is truncated.
+    for i := 0; i < len(data); i++ {
+        if _, err := Decode(data[:i]); err == nil {
+            t.Errorf("Decode of %d of %d bytes didn't fail", i, len(data))
+        }
+    }
+    for _, tt := range []struct {
+        data string
+        want string
+    }{
+        {string(data) + "\x00", "1 bytes after the program"},
+        {MAGIC + "\x01\x04subx\x00\x00", "unknown instruction \"subx\""},
+        {MAGIC + "\x01\x03mul\x00\x01\x00\x05\x02", "5 out of range 0..1"},
+        {MAGIC + "\x01\x03jmp\x00\x01\x00\x02", "2 out of range 0..1"},
+        {MAGIC + "\x00\x00\x01\x00", "instructions without mnemonics"},
+        {MAGIC + "\x00\x01\x05\x01a\x00", "label a at 5 after the end"},
+    } {
+        if _, err := Decode([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.want) {
+            t.Errorf("Decode(%q) error = %v, want %q", tt.data, err, tt.want)
+        }
+    }
+}
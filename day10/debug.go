@@ -0,0 +1,224 @@
+package day10
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// --------------------------------- Debugger ---------------------------------
+
+const debugHelp = `commands:
+  step [n]            run n cycles, 1 if omitted
+  continue            run until a breakpoint, a watched register changes or the program ends
+  break cycle <n>     stop before cycle n
+  break pc <n>        stop before the instruction with index n is fetched
+  break               list the breakpoints
+  delete              remove all breakpoints and watches
+  watch <register>    stop when the register changes
+  print               show the state of the CPU
+  screen              show the CRT
+  quit                leave the debugger, the program runs to its end
+`
+
+// Debugger steps through the program of a device, stopping at breakpoints
+// and on changes of watched registers. At every stop it shows the state of
+// the CPU and the CRT drawn so far.
+type Debugger struct {
+    device *Device
+    out io.Writer
+    cycleBreaks map[int]bool
+    pcBreaks map[int]bool
+    // watches holds the indices of the watched registers.
+    watches map[int]bool
+}
+
+func NewDebugger(device *Device, out io.Writer) *Debugger {
+    return &Debugger{
+        device: device,
+        out: out,
+        cycleBreaks: make(map[int]bool),
+        pcBreaks: make(map[int]bool),
+        watches: make(map[int]bool),
+    }
+}
+
+// Run reads commands from in until "quit" or the end of in. Failing
+// commands are reported to the output, only reading errors are returned.
+func (d *Debugger) Run(in io.Reader) error {
+    scanner := bufio.NewScanner(in)
+    d.stop("")
+    for {
+        fmt.Fprint(d.out, "(day10) ")
+        if !scanner.Scan() {
+            fmt.Fprintln(d.out)
+            return scanner.Err()
+        }
+        quit, err := d.Exec(scanner.Text())
+        if err != nil {
+            fmt.Fprintln(d.out, "error:", err)
+        }
+        if quit {
+            return nil
+        }
+    }
+}
+
+// Exec executes a single command and reports whether it was "quit".
+func (d *Debugger) Exec(command string) (bool, error) {
+    args := strings.Fields(command)
+    if len(args) == 0 {
+        return false, nil
+    }
+
+    switch args[0] {
+    case "step", "s":
+        n := 1
+        if len(args) > 2 {
+            return false, fmt.Errorf("usage: step [n]")
+        }
+        if len(args) == 2 {
+            var err error
+            if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+                return false, fmt.Errorf("invalid number of cycles %q", args[1])
+            }
+        }
+        reason := ""
+        for i := 0; i < n && !d.device.Done(); i++ {
+            reason = d.step()
+        }
+        d.stop(reason)
+    case "continue", "c":
+        reason := ""
+        for !d.device.Done() {
+            if reason = d.step(); reason != "" {
+                break
+            }
+        }
+        d.stop(reason)
+    case "break", "b":
+        return false, d.setBreak(args[1:])
+    case "delete":
+        d.cycleBreaks = make(map[int]bool)
+        d.pcBreaks = make(map[int]bool)
+        d.watches = make(map[int]bool)
+    case "watch", "w":
+        if len(args) != 2 {
+            return false, fmt.Errorf("usage: watch <register>")
+        }
+        reg := registerIndex(strings.ToLower(args[1]))
+        if reg < 0 {
+            return false, fmt.Errorf("unknown register %q", args[1])
+        }
+        d.watches[reg] = true
+    case "print", "p":
+        d.printState()
+    case "screen":
+        fmt.Fprint(d.out, d.device.Screen)
+    case "help", "h":
+        fmt.Fprint(d.out, debugHelp)
+    case "quit", "q":
+        return true, nil
+    default:
+        return false, fmt.Errorf("unknown command %q, try help", args[0])
+    }
+    return false, nil
+}
+
+func (d *Debugger) setBreak(args []string) error {
+    if len(args) == 0 {
+        for _, cycle := range sortedKeys(d.cycleBreaks) {
+            fmt.Fprintf(d.out, "break cycle %d\n", cycle)
+        }
+        for _, pc := range sortedKeys(d.pcBreaks) {
+            fmt.Fprintf(d.out, "break pc %d\n", pc)
+        }
+        for _, reg := range sortedKeys(d.watches) {
+            fmt.Fprintf(d.out, "watch %s\n", Registers[reg])
+        }
+        return nil
+    }
+
+    if len(args) != 2 {
+        return fmt.Errorf("usage: break cycle <n> or break pc <n>")
+    }
+    n, err := strconv.Atoi(args[1])
+    if err != nil || n < 0 {
+        return fmt.Errorf("invalid %s %q", args[0], args[1])
+    }
+    switch args[0] {
+    case "cycle":
+        d.cycleBreaks[n] = true
+    case "pc":
+        d.pcBreaks[n] = true
+    default:
+        return fmt.Errorf("usage: break cycle <n> or break pc <n>")
+    }
+    return nil
+}
+
+// step runs a cycle and returns why the debugger has to stop before the
+// next one, "" if it doesn't.
+func (d *Debugger) step() string {
+    cpu := d.device.Cpu
+    var before []int
+    for reg := range Registers {
+        before = append(before, *cpu.Reg(reg))
+    }
+
+    d.device.Step()
+
+    var reasons []string
+    for _, reg := range sortedKeys(d.watches) {
+        if now := *cpu.Reg(reg); now != before[reg] {
+            reasons = append(reasons, fmt.Sprintf("%s changed from %d to %d", Registers[reg], before[reg], now))
+        }
+    }
+    if d.cycleBreaks[d.device.Cycle] {
+        reasons = append(reasons, fmt.Sprintf("breakpoint at cycle %d", d.device.Cycle))
+    }
+    if !cpu.Processing() && d.pcBreaks[cpu.PC()] {
+        reasons = append(reasons, fmt.Sprintf("breakpoint at pc %d", cpu.PC()))
+    }
+    return strings.Join(reasons, ", ")
+}
+
+// stop shows why the debugger stopped, the state of the CPU and the CRT.
+func (d *Debugger) stop(reason string) {
+    if reason != "" {
+        fmt.Fprintln(d.out, reason)
+    }
+    d.printState()
+    fmt.Fprint(d.out, d.device.Screen)
+}
+
+func (d *Debugger) printState() {
+    cpu := d.device.Cpu
+    if d.device.Done() {
+        fmt.Fprintf(d.out, "done after %d cycles\n", d.device.Cycle-1)
+    } else {
+        ins := cpu.Current()
+        left := ins.Cycles()
+        if cpu.Processing() {
+            left = cpu.Delay()
+        }
+        fmt.Fprintf(d.out, "cycle %d, pc %d: %v (%d of %d cycles left)\n", d.device.Cycle, cpu.PC(), ins, left, ins.Cycles())
+    }
+    var regs []string
+    for i, name := range Registers {
+        regs = append(regs, fmt.Sprintf("%s = %d", strings.ToUpper(name), *cpu.Reg(i)))
+    }
+    fmt.Fprintln(d.out, strings.Join(regs, ", "))
+}
+
+func sortedKeys(m map[int]bool) []int {
+    var keys []int
+    for k := range m {
+        keys = append(keys, k)
+    }
+    sort.Ints(keys)
+    return keys
+}
@@ -0,0 +1,88 @@
+package day10
+
+import (
+    "fmt"
+    "strings"
+)
+
+// ----------------------------------- OCR ------------------------------------
+
+const (
+    GLYPH_WIDTH = 4
+    GLYPH_HEIGHT = 6
+    // GLYPH_SPACING is the width of a glyph including the empty column
+    // separating it from the next one.
+    GLYPH_SPACING = GLYPH_WIDTH + 1
+)
+
+// Font maps the rows of the capital letters drawn by the puzzles to the
+// letters. Not all letters appear in puzzles, so some are missing, as are
+// letters like Y which are wider than GLYPH_WIDTH.
+var Font = map[string]rune{
+    glyph(".##.", "#..#", "#..#", "####", "#..#", "#..#"): 'A',
+    glyph("###.", "#..#", "###.", "#..#", "#..#", "###."): 'B',
+    glyph(".##.", "#..#", "#...", "#...", "#..#", ".##."): 'C',
+    glyph("####", "#...", "###.", "#...", "#...", "####"): 'E',
+    glyph("####", "#...", "###.", "#...", "#...", "#..."): 'F',
+    glyph(".##.", "#..#", "#...", "#.##", "#..#", ".###"): 'G',
+    glyph("#..#", "#..#", "####", "#..#", "#..#", "#..#"): 'H',
+    glyph(".###", "..#.", "..#.", "..#.", "..#.", ".###"): 'I',
+    glyph("..##", "...#", "...#", "...#", "#..#", ".##."): 'J',
+    glyph("#..#", "#.#.", "##..", "#.#.", "#.#.", "#..#"): 'K',
+    glyph("#...", "#...", "#...", "#...", "#...", "####"): 'L',
+    glyph(".##.", "#..#", "#..#", "#..#", "#..#", ".##."): 'O',
+    glyph("###.", "#..#", "#..#", "###.", "#...", "#..."): 'P',
+    glyph("###.", "#..#", "#..#", "###.", "#.#.", "#..#"): 'R',
+    glyph(".###", "#...", "#...", ".##.", "...#", "###."): 'S',
+    glyph("#..#", "#..#", "#..#", "#..#", "#..#", ".##."): 'U',
+    glyph("####", "...#", "..#.", ".#..", "#...", "####"): 'Z',
+}
+
+func glyph(rows ...string) string {
+    return strings.Join(rows, "\n")
+}
+
+// Decode reads the letters drawn on the screen, one per GLYPH_SPACING
+// columns. Unrecognised glyphs are returned as '?' together with an error
+// showing them.
+func (s *Screen) Decode() (string, error) {
+    if s.Height() != GLYPH_HEIGHT {
+        return "", fmt.Errorf("screen height %d, glyphs are %d pixels high", s.Height(), GLYPH_HEIGHT)
+    }
+
+    var (
+        text strings.Builder
+        unknown []string
+    )
+    for i := 0; i*GLYPH_SPACING < s.Width(); i++ {
+        rows := make([]string, GLYPH_HEIGHT)
+        spacing := true
+        for y := range rows {
+            var row strings.Builder
+            for x := i * GLYPH_SPACING; x < i*GLYPH_SPACING+GLYPH_SPACING && x < s.Width(); x++ {
+                lit := s.Lit(x, y)
+                switch {
+                case x-i*GLYPH_SPACING == GLYPH_WIDTH:
+                    spacing = spacing && !lit
+                case lit:
+                    row.WriteByte('#')
+                default:
+                    row.WriteByte('.')
+                }
+            }
+            rows[y] = row.String()
+        }
+
+        letter, ok := Font[glyph(rows...)]
+        if !ok || !spacing {
+            letter = '?'
+            unknown = append(unknown, fmt.Sprintf("glyph %d:\n%s", i+1, glyph(rows...)))
+        }
+        text.WriteRune(letter)
+    }
+
+    if len(unknown) > 0 {
+        return text.String(), fmt.Errorf("unrecognised glyphs in %q:\n%s", text.String(), strings.Join(unknown, "\n"))
+    }
+    return text.String(), nil
+}
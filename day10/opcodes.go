@@ -0,0 +1,155 @@
+package day10
+
+import (
+    "fmt"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// --------------------------------- Opcodes ----------------------------------
+
+// Operand is the kind of an operand of an instruction.
+type Operand int
+
+const (
+    // Imm is an integer like "-5".
+    Imm Operand = iota
+    // Reg is the name of a register, see Registers. Its value is the
+    // index of the register.
+    Reg
+    // Label is the name of a label defined by a line like "loop:". Its
+    // value is the index of the instruction following the label.
+    Label
+)
+
+func (o Operand) String() string {
+    return [...]string{"<integer>", "<register>", "<label>"}[o]
+}
+
+// Opcode describes an instruction of the CPU.
+type Opcode struct {
+    Mnemonic string
+    // Cycles is the number of cycles the instruction takes. It takes
+    // effect at the end of its last cycle.
+    Cycles int
+    Operands []Operand
+    // Exec applies the instruction to the CPU, the values of the operands
+    // are passed in args. The program counter already points to the next
+    // instruction.
+    Exec func(cpu *Cpu, args []int)
+}
+
+var opcodes = make(map[string]*Opcode)
+
+// Define adds an opcode to the instruction set. It panics if the mnemonic
+// is defined twice.
+func Define(op *Opcode) {
+    if op.Cycles < 1 {
+        panic(fmt.Sprintf("day10: opcode %s takes %d cycles", op.Mnemonic, op.Cycles))
+    }
+    if _, dup := opcodes[op.Mnemonic]; dup {
+        panic(fmt.Sprintf("day10: opcode %s defined twice", op.Mnemonic))
+    }
+    opcodes[op.Mnemonic] = op
+}
+
+// LookupOpcode returns the opcode with the given mnemonic.
+func LookupOpcode(mnemonic string) (*Opcode, bool) {
+    op, ok := opcodes[mnemonic]
+    return op, ok
+}
+
+// Mnemonics returns the mnemonics of all opcodes in alphabetical order.
+func Mnemonics() []string {
+    var mnemonics []string
+    for mnemonic := range opcodes {
+        mnemonics = append(mnemonics, mnemonic)
+    }
+    sort.Strings(mnemonics)
+    return mnemonics
+}
+
+// Usage returns the syntax of the instruction like "addx <integer>".
+func (op *Opcode) Usage() string {
+    usage := op.Mnemonic
+    for _, operand := range op.Operands {
+        usage += " " + operand.String()
+    }
+    return usage
+}
+
+// The instruction set of the puzzle, noop and addx, extended by a second
+// register, multiplication and jumps.
+func init() {
+    Define(&Opcode{Mnemonic: "noop", Cycles: 1, Exec: func(*Cpu, []int) {}})
+    Define(&Opcode{Mnemonic: "addx", Cycles: 2, Operands: []Operand{Imm}, Exec: func(cpu *Cpu, args []int) {
+        cpu.X += args[0]
+    }})
+    Define(&Opcode{Mnemonic: "addy", Cycles: 2, Operands: []Operand{Imm}, Exec: func(cpu *Cpu, args []int) {
+        cpu.Y += args[0]
+    }})
+    Define(&Opcode{Mnemonic: "mul", Cycles: 3, Operands: []Operand{Reg, Imm}, Exec: func(cpu *Cpu, args []int) {
+        *cpu.Reg(args[0]) *= args[1]
+    }})
+    Define(&Opcode{Mnemonic: "jmp", Cycles: 1, Operands: []Operand{Label}, Exec: func(cpu *Cpu, args []int) {
+        cpu.Jump(args[0])
+    }})
+    Define(&Opcode{Mnemonic: "jnz", Cycles: 1, Operands: []Operand{Reg, Label}, Exec: func(cpu *Cpu, args []int) {
+        if *cpu.Reg(args[0]) != 0 {
+            cpu.Jump(args[1])
+        }
+    }})
+}
+
+// ------------------------------- Instructions -------------------------------
+
+// Instruction is a single instruction of a program, which executes itself.
+type Instruction interface {
+    // Cycles returns the number of cycles the instruction takes.
+    Cycles() int
+    // Exec applies the instruction at the end of its last cycle.
+    Exec(cpu *Cpu)
+    String() string
+}
+
+// Op is an opcode applied to the values of its operands. Labels holds the
+// names of label operands for printing, "" for other operands.
+type Op struct {
+    Opcode *Opcode
+    Args []int
+    Labels []string
+}
+
+// NewOp returns the instruction of the opcode with the given mnemonic. It
+// panics if there is no such opcode or the number of arguments is wrong.
+func NewOp(mnemonic string, args ...int) Op {
+    op, ok := LookupOpcode(mnemonic)
+    if !ok || len(args) != len(op.Operands) {
+        panic(fmt.Sprintf("day10: invalid instruction %s %v", mnemonic, args))
+    }
+    return Op{op, args, make([]string, len(args))}
+}
+
+func (ins Op) Cycles() int {
+    return ins.Opcode.Cycles
+}
+
+func (ins Op) Exec(cpu *Cpu) {
+    ins.Opcode.Exec(cpu, ins.Args)
+}
+
+func (ins Op) String() string {
+    parts := []string{ins.Opcode.Mnemonic}
+    for i, operand := range ins.Opcode.Operands {
+        switch {
+        case operand == Reg:
+            parts = append(parts, Registers[ins.Args[i]])
+        case operand == Label && ins.Labels[i] != "":
+            parts = append(parts, ins.Labels[i])
+        default:
+            parts = append(parts, strconv.Itoa(ins.Args[i]))
+        }
+    }
+    return strings.Join(parts, " ")
+}
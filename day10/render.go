@@ -0,0 +1,109 @@
+package day10
+
+import (
+    "bufio"
+    "fmt"
+    "image"
+    "image/color"
+    "image/png"
+    "io"
+    "sort"
+)
+
+// --------------------------------- Renderers --------------------------------
+
+// Renderer writes the image of a screen to w.
+type Renderer func(w io.Writer, s *Screen) error
+
+// Renderers maps the names accepted by the -render-format flag to the
+// renderers.
+var Renderers = map[string]Renderer{
+    "text": RenderText,
+    "ansi": RenderANSI,
+    "png": RenderPNG,
+}
+
+// RendererNames returns the names of Renderers in alphabetical order.
+func RendererNames() []string {
+    var names []string
+    for name := range Renderers {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// The colours of the pixels in images, those of a green phosphor CRT.
+var (
+    LitColor = color.RGBA{0x66, 0xff, 0x66, 0xff}
+    DarkColor = color.RGBA{0x10, 0x30, 0x10, 0xff}
+    UndrawnColor = color.RGBA{0x00, 0x00, 0x00, 0xff}
+)
+
+// PIXEL_SIZE is the number of image pixels along each side of a screen
+// pixel in PNG images.
+const PIXEL_SIZE = 8
+
+// RenderText writes the glyphs of the screen.
+func RenderText(w io.Writer, s *Screen) error {
+    _, err := io.WriteString(w, s.String())
+    return err
+}
+
+// RenderANSI colours the background of two spaces per pixel with ANSI
+// true-colour escape sequences, so the pixels are about square.
+func RenderANSI(w io.Writer, s *Screen) error {
+    bw := bufio.NewWriter(w)
+    for y := 0; y < s.Height(); y++ {
+        for x := 0; x < s.Width(); x++ {
+            c := pixelColor(s, x, y)
+            fmt.Fprintf(bw, "\x1b[48;2;%d;%d;%dm  ", c.R, c.G, c.B)
+        }
+        fmt.Fprint(bw, "\x1b[0m\n")
+    }
+    return bw.Flush()
+}
+
+// RenderPNG writes a PNG image with PIXEL_SIZE squares per pixel.
+func RenderPNG(w io.Writer, s *Screen) error {
+    img := image.NewRGBA(image.Rect(0, 0, s.Width()*PIXEL_SIZE, s.Height()*PIXEL_SIZE))
+    for y := 0; y < img.Bounds().Dy(); y++ {
+        for x := 0; x < img.Bounds().Dx(); x++ {
+            img.SetRGBA(x, y, pixelColor(s, x/PIXEL_SIZE, y/PIXEL_SIZE))
+        }
+    }
+    return png.Encode(w, img)
+}
+
+func pixelColor(s *Screen, x, y int) color.RGBA {
+    switch {
+    case !s.Drawn(x, y):
+        return UndrawnColor
+    case s.Lit(x, y):
+        return LitColor
+    default:
+        return DarkColor
+    }
+}
+
+// render writes the screen to the file named by Render in RenderFormat.
+func (s Solver) render(screen *Screen) error {
+    format := s.RenderFormat
+    if format == "" {
+        format = "text"
+    }
+    render, ok := Renderers[format]
+    if !ok {
+        return fmt.Errorf("unknown render format %q", format)
+    }
+
+    w, close, err := s.create(s.Render)
+    if err != nil {
+        return err
+    }
+    err = render(w, screen)
+    if cerr := close(); err == nil {
+        err = cerr
+    }
+    return err
+}
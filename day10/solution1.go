@@ -1,149 +1,111 @@
-package main
+package day10
 
 import (
-    "bufio"
     "fmt"
+    "io"
     "os"
-    "strings"
-    "strconv"
-)
-
-// ------------------------------- Instructions -------------------------------
-
-type Instruction interface {
-    Delay() int
-}
-
-type AddX struct {
-    Amount int
-    delay int
-}
-
-type Noop struct {
-    delay int
-}
-
-func NewAddx(amount int) AddX {
-    return AddX{amount, 2}
-}
-
-func NewNoop() Noop {
-    return Noop{1}
-}
-
-func (ins AddX) Delay() int {
-    return ins.delay
-}
 
-func (ins Noop) Delay() int {
-    return ins.delay
-}
-
-// ----------------------------------- CPU ------------------------------------
-
-type Cpu struct {
-    X int
-    pc int
-    program []Instruction
-    delay int
-}
-
-func NewCpu(program []Instruction) *Cpu {
-    return &Cpu{X: 1, pc: 0, program: program, delay: 0}
-}
-
-func (cpu *Cpu) Fetch() {
-    cpu.delay = cpu.program[cpu.pc].Delay()
-}
-
-func (cpu *Cpu) Exec() {
-    cpu.delay--
+    "aoc/solver"
+)
 
-    if cpu.delay > 0 {
-        return
+func (s Solver) Part1(r io.Reader) (solver.Answer, error) {
+    program, err := LoadProgram(r)
+    if err != nil {
+        return "", err
     }
 
-    var ins = cpu.program[cpu.pc]
-
-    switch ins.(type) {
-    case AddX:
-        addx, _ := ins.(AddX)
-        cpu.X += addx.Amount
-    case Noop:
-        ;
-    default:
-        panic("unreachable line")
+    var query = s.Query
+    if len(query.Cycles) == 0 && query.Every <= 0 {
+        query.Cycles = Checkpoints
     }
 
-    cpu.pc++
-}
-
-func (cpu *Cpu) Processing() bool {
-    return cpu.delay > 0
-}
-
-func (cpu *Cpu) Ready() bool {
-    return cpu.pc == len(cpu.program)
-}
+    tracer, done, err := s.tracer()
+    if err != nil {
+        return "", err
+    }
 
-// ----------------------------------- Main -----------------------------------
+    if err := s.Screen.Validate(); err != nil {
+        return "", err
+    }
 
-func EachLineDo(f func(string)) error {
-    scanner := bufio.NewScanner(os.Stdin)
+    var maxCycles = s.MaxCycles
+    if maxCycles <= 0 {
+        maxCycles = DefaultMaxCycles
+    }
 
-    for scanner.Scan() {
-        line := scanner.Text()
-        f(line)
+    // A trace covers the whole program, otherwise it runs until the last
+    // cycle queried. Programs may loop forever, so either stops after
+    // maxCycles.
+    var device = NewDevice(program, NewScreen(s.Screen))
+    var totalSignalStrength = 0
+    for !device.Cpu.Ready() && (tracer != nil || query.Last() < 0 || device.Cycle <= query.Last()) {
+        if device.Cycle > maxCycles {
+            done()
+            return "", fmt.Errorf("program still running after %d cycles", maxCycles)
+        }
+        e := device.Step()
+        if query.Match(e.Cycle) {
+            totalSignalStrength += e.SignalStrength()
+        }
+        if tracer != nil {
+            if err := tracer.Trace(e); err != nil {
+                done()
+                return "", err
+            }
+        }
     }
 
-    err := scanner.Err()
+    if err := done(); err != nil {
+        return "", err
+    }
 
-    return err
+    return solver.Int(totalSignalStrength), nil
 }
 
-func main() {
-    var program []Instruction
+// tracer returns the tracer selected by the solver, nil if none is, and a
+// function flushing it and closing its file.
+func (s Solver) tracer() (Tracer, func() error, error) {
+    if s.Trace == "" {
+        return nil, func() error { return nil }, nil
+    }
 
-    err := EachLineDo(func(line string) {
-        split := strings.Split(line, " ")
-        switch split[0] {
-        case "addx":
-            amount, _ := strconv.Atoi(split[1])
-            program = append(program, NewAddx(amount))
-        case "noop":
-            program = append(program, NewNoop())
-        default:
-            panic("unreachable line")
-        }
-    })
+    format := s.TraceFormat
+    if format == "" {
+        format = "csv"
+    }
+    newTracer, ok := TraceFormats[format]
+    if !ok {
+        return nil, nil, fmt.Errorf("unknown trace format %q", format)
+    }
 
+    w, close, err := s.create(s.Trace)
     if err != nil {
-        fmt.Fprintf(os.Stderr, "reading stdin:", err)
-        os.Exit(1)
+        return nil, nil, err
     }
-
-    var cpu = NewCpu(program)
-    var cycle = 1
-    var checkpoints = [...]int{220, 180, 140, 100, 60, 20}
-    var totalSignalStrength = 0
-
-    for !cpu.Ready() {
-        for _, checkpoint := range checkpoints {
-            if cycle == checkpoint {
-                totalSignalStrength += cycle * cpu.X
-                break
-            }
+    tracer := newTracer(w)
+    return tracer, func() error {
+        err := tracer.Flush()
+        if cerr := close(); err == nil {
+            err = cerr
         }
+        return err
+    }, nil
+}
 
-        if !cpu.Processing() {
-            cpu.Fetch()
-            cpu.Exec()
-        } else {
-            cpu.Exec()
+// create creates the named file, "-" for Out, and returns it with a
+// function closing it.
+func (s Solver) create(name string) (io.Writer, func() error, error) {
+    if name == "-" {
+        out := s.Out
+        if out == nil {
+            out = os.Stdout
         }
-
-        cycle++
+        return out, func() error { return nil }, nil
     }
 
-    fmt.Println("Sum of signal strengths:", totalSignalStrength)
+    f, err := os.Create(name)
+    if err != nil {
+        return nil, nil, err
+    }
+    return f, f.Close, nil
 }
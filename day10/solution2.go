@@ -1,188 +1,227 @@
-package main
+package day10
 
 import (
-    "bufio"
     "fmt"
+    "io"
     "os"
     "strings"
-    "strconv"
-)
-
-// ------------------------------- Instructions -------------------------------
 
-type Instruction interface {
-    Delay() int
-}
+    "aoc/solver"
+)
 
-type AddX struct {
-    Amount int
-    delay int
-}
+func (s Solver) Part2(r io.Reader) (solver.Answer, error) {
+    program, err := LoadProgram(r)
+    if err != nil {
+        return "", err
+    }
 
-type Noop struct {
-    delay int
-}
+    if err := s.Screen.Validate(); err != nil {
+        return "", err
+    }
 
-func NewAddx(amount int) AddX {
-    return AddX{amount, 2}
-}
+    var device = NewDevice(program, NewScreen(s.Screen))
+    if s.Debug {
+        in, out := s.In, s.Out
+        if in == nil {
+            in = os.Stdin
+        }
+        if out == nil {
+            out = os.Stdout
+        }
+        if err := NewDebugger(device, out).Run(in); err != nil {
+            return "", err
+        }
+    }
 
-func NewNoop() Noop {
-    return Noop{1}
-}
+    for !device.Done() {
+        device.Step()
+    }
 
-func (ins AddX) Delay() int {
-    return ins.delay
-}
+    if s.Render != "" {
+        if err := s.render(device.Screen); err != nil {
+            return "", err
+        }
+    }
 
-func (ins Noop) Delay() int {
-    return ins.delay
+    if s.Draw {
+        return solver.Answer(device.Screen.String()), nil
+    }
+    text, err := device.Screen.Decode()
+    if err != nil {
+        return "", err
+    }
+    return solver.Answer(text), nil
 }
 
-// ----------------------------------- CPU ------------------------------------
+// ---------------------------------- Device ----------------------------------
 
-type Cpu struct {
-    X int
-    pc int
-    program []Instruction
-    delay int
+// Device runs a program on the CPU while the CRT draws the pixels.
+type Device struct {
+    Cpu *Cpu
+    Screen *Screen
+    // Cycle is the number of the cycle run next, starting with 1.
+    Cycle int
 }
 
-func NewCpu(program []Instruction) *Cpu {
-    return &Cpu{X: 1, pc: 0, program: program, delay: 0}
+func NewDevice(program []Instruction, screen *Screen) *Device {
+    return &Device{NewCpu(program), screen, 1}
 }
 
-func (cpu *Cpu) Fetch() {
-    cpu.delay = cpu.program[cpu.pc].Delay()
-}
-
-func (cpu *Cpu) Exec() {
-    cpu.delay--
-
-    if cpu.delay > 0 {
-        //fmt.Println("return")
-        return
+// Step runs a single cycle: the CRT draws a pixel with the sprite at X,
+// then the CPU executes the cycle. It returns the trace of the cycle.
+func (d *Device) Step() TraceEntry {
+    e := TraceEntry{
+        Cycle: d.Cycle,
+        PC: d.Cpu.PC(),
+        Instruction: d.Cpu.Current().String(),
+        XBefore: d.Cpu.X,
     }
 
-    var ins = cpu.program[cpu.pc]
-
-    switch ins.(type) {
-    case AddX:
-        addx, _ := ins.(AddX)
-        cpu.X += addx.Amount
-    case Noop:
-        ;
-    default:
-        panic("unreachable line")
+    d.Screen.SetSprite(d.Cpu.X)
+    if pixel := d.Screen.DrawPixel(); pixel != 0 {
+        e.Pixel = string(pixel)
     }
+    d.Cpu.Tick()
+    d.Cycle++
 
-    cpu.pc++
+    e.XAfter = d.Cpu.X
+    return e
 }
 
-func (cpu *Cpu) Processing() bool {
-    return cpu.delay > 0
+// Done reports whether the program ended or the screen is full.
+func (d *Device) Done() bool {
+    return d.Cpu.Ready() || d.Screen.Full()
 }
 
-func (cpu *Cpu) Ready() bool {
-    return cpu.pc == len(cpu.program)
+// ---------------------------------- Screen ----------------------------------
+
+// ScreenOptions configure a screen. Zero fields take the values of
+// DefaultScreen.
+type ScreenOptions struct {
+    Width, Height int
+    // SpriteWidth is the number of pixels covered by the sprite, centred
+    // on X. Even widths extend further to the right.
+    SpriteWidth int
+    // Lit and Dark are the glyphs the pixels are drawn with.
+    Lit, Dark rune
 }
 
-// ---------------------------------- Screen ----------------------------------
+// DefaultScreen is the CRT of the puzzle.
+var DefaultScreen = ScreenOptions{Width: 40, Height: 6, SpriteWidth: 3, Lit: '#', Dark: '.'}
 
-const (
-    SCREEN_WIDTH = 40
-    SCREEN_HEIGHT = 6
-)
+func (o ScreenOptions) withDefaults() ScreenOptions {
+    if o.Width == 0 {
+        o.Width = DefaultScreen.Width
+    }
+    if o.Height == 0 {
+        o.Height = DefaultScreen.Height
+    }
+    if o.SpriteWidth == 0 {
+        o.SpriteWidth = DefaultScreen.SpriteWidth
+    }
+    if o.Lit == 0 {
+        o.Lit = DefaultScreen.Lit
+    }
+    if o.Dark == 0 {
+        o.Dark = DefaultScreen.Dark
+    }
+    return o
+}
+
+// Validate reports options no screen can be built with.
+func (o ScreenOptions) Validate() error {
+    o = o.withDefaults()
+    switch {
+    case o.Width < 0 || o.Height < 0:
+        return fmt.Errorf("invalid screen size %dx%d", o.Width, o.Height)
+    case o.SpriteWidth < 0:
+        return fmt.Errorf("invalid sprite width %d", o.SpriteWidth)
+    case o.Lit == o.Dark:
+        return fmt.Errorf("lit and dark pixels both drawn as %q", o.Lit)
+    }
+    return nil
+}
 
 type Screen struct {
-    buffer [SCREEN_WIDTH*SCREEN_HEIGHT]rune
+    opts ScreenOptions
+    // buffer holds the glyphs of the pixels row by row, 0 if not drawn yet.
+    buffer []rune
     row, col int
     sprite int
 }
 
-func NewScreen() *Screen {
-    return &Screen{row: 0, col: 0, sprite: 0}
+// NewScreen returns an empty screen. It panics if the options aren't valid.
+func NewScreen(opts ScreenOptions) *Screen {
+    if err := opts.Validate(); err != nil {
+        panic("day10: " + err.Error())
+    }
+    opts = opts.withDefaults()
+    return &Screen{opts: opts, buffer: make([]rune, opts.Width*opts.Height)}
 }
 
-func (s *Screen) DrawPixel() {
-    if s.col == s.sprite - 1 || s.col == s.sprite || s.col == s.sprite + 1 {
-        s.buffer[s.col + s.row * SCREEN_WIDTH] = '#'
-    } else {
-        s.buffer[s.col + s.row * SCREEN_WIDTH] = '.'
+// DrawPixel draws the next pixel and returns its glyph. Once the screen is
+// full it doesn't draw anything and returns 0.
+func (s *Screen) DrawPixel() rune {
+    if s.Full() {
+        return 0
     }
 
-    if s.col == SCREEN_WIDTH-1 {
+    var pixel = s.opts.Dark
+    if left := s.sprite - (s.opts.SpriteWidth-1)/2; s.col >= left && s.col < left+s.opts.SpriteWidth {
+        pixel = s.opts.Lit
+    }
+    s.buffer[s.col + s.row*s.opts.Width] = pixel
+
+    if s.col == s.opts.Width-1 {
         s.col = 0
         s.row++
     } else {
         s.col++
     }
+    return pixel
 }
 
-func (s *Screen) SetSprite(x int) {
-    s.sprite = x
+// Width returns the number of pixels of a row.
+func (s *Screen) Width() int {
+    return s.opts.Width
 }
 
-func (s *Screen) String() string {
-    var sb strings.Builder
-    for i := 0; i < SCREEN_HEIGHT; i++ {
-        for j := 0; j < SCREEN_WIDTH; j++ {
-            sb.WriteRune(s.buffer[j + i*SCREEN_WIDTH])
-        }
-        sb.WriteRune('\n')
-    }
-    return sb.String()
+// Height returns the number of rows.
+func (s *Screen) Height() int {
+    return s.opts.Height
 }
 
-// ----------------------------------- Main -----------------------------------
-
-func EachLineDo(f func(string)) error {
-    scanner := bufio.NewScanner(os.Stdin)
-
-    for scanner.Scan() {
-        line := scanner.Text()
-        f(line)
-    }
-
-    err := scanner.Err()
-
-    return err
+// Lit reports whether the pixel in column x of row y is lit.
+func (s *Screen) Lit(x, y int) bool {
+    return s.buffer[x + y*s.opts.Width] == s.opts.Lit
 }
 
-func main() {
-    var program []Instruction
-
-    err := EachLineDo(func(line string) {
-        split := strings.Split(line, " ")
-        switch split[0] {
-        case "addx":
-            amount, _ := strconv.Atoi(split[1])
-            program = append(program, NewAddx(amount))
-        case "noop":
-            program = append(program, NewNoop())
-        default:
-            panic("unreachable line")
-        }
-    })
+// Drawn reports whether the pixel in column x of row y is drawn.
+func (s *Screen) Drawn(x, y int) bool {
+    return s.buffer[x + y*s.opts.Width] != 0
+}
 
-    if err != nil {
-        fmt.Fprintf(os.Stderr, "reading stdin:", err)
-        os.Exit(1)
-    }
+// Full reports whether all pixels are drawn.
+func (s *Screen) Full() bool {
+    return s.row == s.opts.Height
+}
 
-    var cpu = NewCpu(program)
-    var screen = NewScreen()
+func (s *Screen) SetSprite(x int) {
+    s.sprite = x
+}
 
-    for !cpu.Ready() {
-        screen.SetSprite(cpu.X)
-        screen.DrawPixel()
-        if !cpu.Processing() {
-            cpu.Fetch()
-            cpu.Exec()
-        } else {
-            cpu.Exec()
+// String draws the screen, pixels not drawn yet as spaces.
+func (s *Screen) String() string {
+    var sb strings.Builder
+    for i := 0; i < s.opts.Height; i++ {
+        for j := 0; j < s.opts.Width; j++ {
+            pixel := s.buffer[j + i*s.opts.Width]
+            if pixel == 0 {
+                pixel = ' '
+            }
+            sb.WriteRune(pixel)
         }
+        sb.WriteRune('\n')
     }
-
-    fmt.Print(screen)
+    return sb.String()
 }
@@ -0,0 +1,156 @@
+package day10
+
+import (
+    "bufio"
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// ---------------------------------- Trace -----------------------------------
+
+// TraceEntry describes a single cycle of a device. Pixel is the pixel the
+// CRT drew during the cycle, empty once the screen is full.
+type TraceEntry struct {
+    Cycle int `json:"cycle"`
+    PC int `json:"pc"`
+    Instruction string `json:"instruction"`
+    XBefore int `json:"x_before"`
+    XAfter int `json:"x_after"`
+    Pixel string `json:"pixel"`
+}
+
+// SignalStrength returns the signal strength during the cycle, its number
+// times the value of X.
+func (e TraceEntry) SignalStrength() int {
+    return e.Cycle * e.XBefore
+}
+
+// Tracer writes trace entries in some format.
+type Tracer interface {
+    Trace(e TraceEntry) error
+    // Flush writes any buffered entries.
+    Flush() error
+}
+
+// TraceFormats maps the names accepted by the -trace-format flag to the
+// tracers.
+var TraceFormats = map[string]func(w io.Writer) Tracer{
+    "csv": NewCSVTracer,
+    "jsonl": NewJSONLTracer,
+}
+
+// TraceFormatNames returns the names of TraceFormats in alphabetical order.
+func TraceFormatNames() []string {
+    var names []string
+    for name := range TraceFormats {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+type csvTracer struct {
+    w *csv.Writer
+    header bool
+}
+
+// NewCSVTracer writes the entries as CSV with a header line.
+func NewCSVTracer(w io.Writer) Tracer {
+    return &csvTracer{w: csv.NewWriter(w)}
+}
+
+func (t *csvTracer) Trace(e TraceEntry) error {
+    if !t.header {
+        t.header = true
+        if err := t.w.Write([]string{"cycle", "pc", "instruction", "x_before", "x_after", "pixel"}); err != nil {
+            return err
+        }
+    }
+    return t.w.Write([]string{
+        strconv.Itoa(e.Cycle),
+        strconv.Itoa(e.PC),
+        e.Instruction,
+        strconv.Itoa(e.XBefore),
+        strconv.Itoa(e.XAfter),
+        e.Pixel,
+    })
+}
+
+func (t *csvTracer) Flush() error {
+    t.w.Flush()
+    return t.w.Error()
+}
+
+type jsonlTracer struct {
+    w *bufio.Writer
+    enc *json.Encoder
+}
+
+// NewJSONLTracer writes the entries as JSON Lines, one object per cycle.
+func NewJSONLTracer(w io.Writer) Tracer {
+    bw := bufio.NewWriter(w)
+    return &jsonlTracer{bw, json.NewEncoder(bw)}
+}
+
+func (t *jsonlTracer) Trace(e TraceEntry) error {
+    return t.enc.Encode(e)
+}
+
+func (t *jsonlTracer) Flush() error {
+    return t.w.Flush()
+}
+
+// ---------------------------------- Query -----------------------------------
+
+// Checkpoints are the cycles whose signal strengths are summed up in part 1.
+var Checkpoints = []int{20, 60, 100, 140, 180, 220}
+
+// Query selects the cycles whose signal strengths are summed up: the
+// listed Cycles, or if Every is positive every Every-th cycle.
+type Query struct {
+    Cycles []int
+    Every int
+}
+
+// Match reports whether the query selects the cycle.
+func (q Query) Match(cycle int) bool {
+    if q.Every > 0 {
+        return cycle%q.Every == 0
+    }
+    for _, c := range q.Cycles {
+        if c == cycle {
+            return true
+        }
+    }
+    return false
+}
+
+// Last returns the last cycle the query selects, -1 if it has no end.
+func (q Query) Last() int {
+    if q.Every > 0 {
+        return -1
+    }
+    last := 0
+    for _, c := range q.Cycles {
+        last = max(last, c)
+    }
+    return last
+}
+
+// parseCycles parses a comma separated list of cycles like "20,60,100".
+func parseCycles(value string) ([]int, error) {
+    var cycles []int
+    for _, f := range strings.Split(value, ",") {
+        cycle, err := strconv.Atoi(strings.TrimSpace(f))
+        if err != nil || cycle < 1 {
+            return nil, fmt.Errorf("invalid cycle %q", f)
+        }
+        cycles = append(cycles, cycle)
+    }
+    return cycles, nil
+}
@@ -0,0 +1,102 @@
+package day11
+
+import (
+    "fmt"
+    "sync"
+)
+
+// -------------------------------- Concurrent --------------------------------
+
+// RunConcurrent plays n rounds like Run, but every monkey is a goroutine
+// owning the items it holds. The items travel to the other monkies over
+// unbuffered channels. A token passed from one monkey to the next orders
+// the turns: as a throw completes when the target received the item, every
+// monkey holds all items thrown to it in the current round before it gets
+// the token. The last monkey hands the token back, which ends the round.
+func (t *Troop) RunConcurrent(n int) error {
+    var (
+        played int
+        err error
+    )
+    if t.modItems != nil {
+        played, err = playConcurrent(t.modItems, t.inspected, n, t.inspectMod)
+    } else {
+        played, err = playConcurrent(t.items, t.inspected, n, t.inspectBig)
+    }
+    t.round += played
+    if err != nil {
+        return fmt.Errorf("round %d: %w", t.round+1, err)
+    }
+    return nil
+}
+
+// playConcurrent plays the given number of rounds on items and returns the
+// number of rounds completed. Monkey i only touches items[i] and
+// inspected[i] until all goroutines stopped.
+func playConcurrent[T any](items [][]T, inspected []int, rounds int, inspect inspectFunc[T]) (int, error) {
+    n := len(items)
+    inboxes := make([]chan T, n)
+    // The token of monkey i is sent on tokens[i], tokens[n] ends the round.
+    // It carries the error of a failed turn, after which the remaining
+    // monkies of the round pass it on without taking their turn.
+    tokens := make([]chan error, n+1)
+    for i := range inboxes {
+        inboxes[i] = make(chan T)
+        tokens[i] = make(chan error)
+    }
+    tokens[n] = make(chan error)
+
+    var wg sync.WaitGroup
+    for i := 0; i < n; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            for {
+                select {
+                case worryLevel := <-inboxes[i]:
+                    items[i] = append(items[i], worryLevel)
+                case err, ok := <-tokens[i]:
+                    if !ok {
+                        return
+                    }
+                    if err == nil {
+                        err = throwConcurrent(items, inspected, i, inboxes, inspect)
+                    }
+                    tokens[i+1] <- err
+                }
+            }
+        }(i)
+    }
+
+    var (
+        played int
+        err error
+    )
+    for ; played < rounds; played++ {
+        tokens[0] <- nil
+        if err = <-tokens[n]; err != nil {
+            break
+        }
+    }
+
+    for i := 0; i < n; i++ {
+        close(tokens[i])
+    }
+    wg.Wait()
+    return played, err
+}
+
+// throwConcurrent is the turn of monkey i, throwing to the inboxes.
+func throwConcurrent[T any](items [][]T, inspected []int, i int, inboxes []chan T, inspect inspectFunc[T]) error {
+    for j, worryLevel := range items[i] {
+        worryLevel, target, err := inspect(i, worryLevel)
+        if err != nil {
+            items[i] = items[i][j:]
+            return err
+        }
+        inspected[i]++
+        inboxes[target] <- worryLevel
+    }
+    items[i] = items[i][:0]
+    return nil
+}
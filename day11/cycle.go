@@ -0,0 +1,181 @@
+package day11
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "math/big"
+    "sort"
+)
+
+// ErrNotModular is returned for the per-item analysis of troops which don't
+// play on worry levels reduced modulo an uint64.
+var ErrNotModular = errors.New("worry levels are not reduced modulo an uint64")
+
+// -------------------------------- Trajectory --------------------------------
+
+// Inspection is a monkey inspecting an item. WorryLevel is the reduced worry
+// level after the operation was applied, Target the monkey it is thrown to.
+type Inspection struct {
+    Monkey int
+    WorryLevel uint64
+    Target int
+}
+
+// Trajectory is the path of a single item through the monkies. Without
+// relief the items don't influence each other: the monkey holding an item
+// at the start of a round and its worry level determine its inspections in
+// all following rounds. As there are finitely many of these states, the
+// rounds of every item eventually repeat.
+type Trajectory struct {
+    // The state of the item at the start of the first round.
+    Monkey int
+    WorryLevel uint64
+    // Rounds holds the inspections in each round followed.
+    Rounds [][]Inspection
+    // Cycle is the index of the round the item repeats after the last one
+    // of Rounds, -1 if the item wasn't followed long enough.
+    Cycle int
+}
+
+type itemState struct {
+    monkey int
+    worryLevel uint64
+}
+
+// Trajectories follows every item the monkies hold at the current round
+// until its rounds repeat, but for at most limit rounds. The items are in
+// the order of Snapshot.
+func (t *Troop) Trajectories(limit int) ([]*Trajectory, error) {
+    if t.modItems == nil {
+        return nil, ErrNotModular
+    }
+    var trajectories []*Trajectory
+    for i, items := range t.modItems {
+        for _, worryLevel := range items {
+            trajectories = append(trajectories, t.follow(itemState{i, worryLevel}, limit))
+        }
+    }
+    return trajectories, nil
+}
+
+// Trajectory returns the trajectory of the item with the given index in
+// the order of Snapshot, followed for at most limit rounds.
+func (t *Troop) Trajectory(item, limit int) (*Trajectory, error) {
+    if t.modItems == nil {
+        return nil, ErrNotModular
+    }
+    n := item
+    for i, items := range t.modItems {
+        if n < len(items) {
+            return t.follow(itemState{i, items[n]}, limit), nil
+        }
+        n -= len(items)
+    }
+    return nil, fmt.Errorf("no item %d", item)
+}
+
+func (t *Troop) follow(start itemState, limit int) *Trajectory {
+    tr := &Trajectory{Monkey: start.monkey, WorryLevel: start.worryLevel, Cycle: -1}
+    seen := make(map[itemState]int)
+
+    for s := start; len(tr.Rounds) < limit; {
+        if round, ok := seen[s]; ok {
+            tr.Cycle = round
+            break
+        }
+        seen[s] = len(tr.Rounds)
+
+        // The monkies take their turns in order, so an item thrown to a
+        // monkey after the current one is inspected again in this round.
+        var inspections []Inspection
+        for {
+            worryLevel := t.operations[s.monkey](s.worryLevel)
+            target := t.monkies[s.monkey].throwMod(worryLevel)
+            inspections = append(inspections, Inspection{s.monkey, worryLevel, target})
+            moved := target < s.monkey
+            s = itemState{target, worryLevel}
+            if moved {
+                break
+            }
+        }
+        tr.Rounds = append(tr.Rounds, inspections)
+    }
+    return tr
+}
+
+// Inspected adds the number of times each monkey inspects the item in the
+// given number of rounds to inspected. It panics if the item wasn't
+// followed long enough.
+func (tr *Trajectory) Inspected(rounds int, inspected []int) {
+    n := len(tr.Rounds)
+    if rounds <= n {
+        tr.count(0, rounds, 1, inspected)
+        return
+    }
+    if tr.Cycle < 0 {
+        panic(fmt.Sprintf("day11: trajectory of %d rounds without cycle extrapolated to %d rounds", n, rounds))
+    }
+
+    length := n - tr.Cycle
+    cycles, rest := (rounds-tr.Cycle)/length, (rounds-tr.Cycle)%length
+    tr.count(0, tr.Cycle, 1, inspected)
+    tr.count(tr.Cycle, n, cycles, inspected)
+    tr.count(tr.Cycle, tr.Cycle+rest, 1, inspected)
+}
+
+func (tr *Trajectory) count(from, to, times int, inspected []int) {
+    for _, inspections := range tr.Rounds[from:to] {
+        for _, in := range inspections {
+            inspected[in.Monkey] += times
+        }
+    }
+}
+
+// Format writes the trajectory, one line per round.
+func (tr *Trajectory) Format(w io.Writer, firstRound int) {
+    fmt.Fprintf(w, "starts at monkey %d with worry level %d\n", tr.Monkey, tr.WorryLevel)
+    for i, inspections := range tr.Rounds {
+        fmt.Fprintf(w, "round %d:", firstRound+i)
+        for _, in := range inspections {
+            fmt.Fprintf(w, " monkey %d: %d ->", in.Monkey, in.WorryLevel)
+        }
+        fmt.Fprintf(w, " monkey %d\n", inspections[len(inspections)-1].Target)
+    }
+    if tr.Cycle >= 0 {
+        fmt.Fprintf(w, "repeats rounds %d to %d every %d rounds\n",
+            firstRound+tr.Cycle, firstRound+len(tr.Rounds)-1, len(tr.Rounds)-tr.Cycle)
+    }
+}
+
+// ------------------------------- Extrapolation ------------------------------
+
+// Extrapolate returns the number of items each monkey will have inspected
+// after the given number of further rounds. Instead of playing the rounds
+// it follows every item until its rounds repeat, so it takes about as long
+// for any number of rounds. The troop itself isn't changed.
+func (t *Troop) Extrapolate(rounds int) ([]int, error) {
+    if rounds < 0 {
+        return nil, fmt.Errorf("negative number of rounds %d", rounds)
+    }
+    trajectories, err := t.Trajectories(rounds)
+    if err != nil {
+        return nil, err
+    }
+    inspected := append([]int(nil), t.inspected...)
+    for _, tr := range trajectories {
+        tr.Inspected(rounds, inspected)
+    }
+    return inspected, nil
+}
+
+// MonkeyBusiness returns the product of the two largest inspection counts.
+// It doesn't overflow for counts of many rounds.
+func MonkeyBusiness(inspected []int) *big.Int {
+    sorted := append([]int(nil), inspected...)
+    sort.Ints(sorted)
+
+    var nMonkies = len(sorted)
+    business := big.NewInt(int64(sorted[nMonkies-1]))
+    return business.Mul(business, big.NewInt(int64(sorted[nMonkies-2])))
+}
@@ -0,0 +1,432 @@
+// Package day11 solves "Monkey in the Middle".
+package day11
+
+import (
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "math/big"
+    "sort"
+    "strconv"
+    "strings"
+
+    "aoc/input"
+    "aoc/solver"
+)
+
+func init() {
+    solver.Register(11, &Solver{Rounds: DefaultRounds, Engine: DefaultEngine})
+}
+
+// DefaultRounds is the number of rounds played in part 2.
+const DefaultRounds = 10000
+
+// Solver plays Rounds rounds in part 2, DefaultRounds if zero, with the
+// engine named Engine, see Engines. An empty Engine selects DefaultEngine.
+// The paths of the items listed in Trace are written to Log, os.Stderr if
+// nil.
+type Solver struct {
+    Rounds int
+    Engine string
+    Trace []int
+    Log io.Writer
+}
+
+func (s *Solver) Flags(fs *flag.FlagSet) {
+    fs.IntVar(&s.Rounds, "rounds", s.Rounds, "number of rounds in part 2")
+    fs.StringVar(&s.Engine, "engine", s.Engine, "engine playing the rounds: "+strings.Join(EngineNames(), ", "))
+    fs.Func("trace", "print the path of an item in part 2, given as item=N with N counting from 0 in input order", func(value string) error {
+        n, err := parseTrace(value)
+        if err != nil {
+            return err
+        }
+        s.Trace = append(s.Trace, n)
+        return nil
+    })
+}
+
+// play plays the given number of rounds with the selected engine and
+// returns the number of items each monkey inspected.
+func (s Solver) play(troop *Troop, rounds int) ([]int, error) {
+    name := s.Engine
+    if name == "" {
+        name = DefaultEngine
+    }
+    engine, ok := Engines[name]
+    if !ok {
+        return nil, fmt.Errorf("unknown engine %q", name)
+    }
+    return engine(troop, rounds)
+}
+
+func parseTrace(value string) (int, error) {
+    key, item, ok := strings.Cut(value, "=")
+    if !ok || key != "item" {
+        return 0, fmt.Errorf("expected item=N, got %q", value)
+    }
+    n, err := strconv.Atoi(item)
+    if err != nil || n < 0 {
+        return 0, fmt.Errorf("invalid item %q", item)
+    }
+    return n, nil
+}
+
+// --------------------------------- Engines ----------------------------------
+
+// DefaultEngine is the engine used unless another one is selected.
+const DefaultEngine = "cycles"
+
+// Engine plays rounds of a troop and returns the number of items each
+// monkey inspected afterwards.
+type Engine func(troop *Troop, rounds int) ([]int, error)
+
+// Engines maps the names accepted by the -engine flag to the engines. All
+// of them count the same inspections.
+var Engines = map[string]Engine{
+    "sequential": func(troop *Troop, rounds int) ([]int, error) {
+        err := troop.Run(rounds)
+        return troop.Snapshot().Inspected, err
+    },
+    "concurrent": func(troop *Troop, rounds int) ([]int, error) {
+        err := troop.RunConcurrent(rounds)
+        return troop.Snapshot().Inspected, err
+    },
+    // Extrapolate the cycles of the items if the troop plays on uint64.
+    "cycles": func(troop *Troop, rounds int) ([]int, error) {
+        inspected, err := troop.Extrapolate(rounds)
+        if errors.Is(err, ErrNotModular) {
+            err = troop.Run(rounds)
+            return troop.Snapshot().Inspected, err
+        }
+        return inspected, err
+    },
+}
+
+// EngineNames returns the names of Engines in alphabetical order.
+func EngineNames() []string {
+    var names []string
+    for name := range Engines {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// ---------------------------------- Model -----------------------------------
+
+type Test interface {
+    Test(*big.Int) bool
+    // Modulus returns a number such that worry levels congruent modulo it
+    // pass the test alike.
+    Modulus() *big.Int
+    // TestMod tests a worry level reduced modulo a multiple of Modulus,
+    // which fits into an uint64.
+    TestMod(uint64) bool
+}
+
+type Divisible struct {
+    x *big.Int
+}
+
+func (d Divisible) Test(n *big.Int) bool {
+    var result = big.NewInt(0)
+    result.Mod(n, d.x)
+    return result.Cmp(big.NewInt(int64(0))) == 0
+}
+
+func (d Divisible) Modulus() *big.Int {
+    return d.x
+}
+
+func (d Divisible) TestMod(n uint64) bool {
+    return n%d.x.Uint64() == 0
+}
+
+// Monkey describes how a monkey treats the items it holds. It doesn't
+// change while playing, the state of a game is held by a Troop.
+type Monkey struct {
+    id int
+    items []*big.Int
+    operation Expr
+    test Test
+    targets [2]int
+}
+
+func NewMonkey(id int, items []*big.Int, operation Expr, test Test, targets [2]int) *Monkey {
+    return &Monkey{id, items, operation, test, targets}
+}
+
+// Throw returns the monkey the item with the given worry level is thrown
+// to, after the operation and relief were applied.
+func (m *Monkey) Throw(worryLevel *big.Int) int {
+    if m.test.Test(worryLevel) {
+        return m.targets[0]
+    }
+    return m.targets[1]
+}
+
+// throwMod is Throw for a worry level reduced modulo an uint64.
+func (m *Monkey) throwMod(worryLevel uint64) int {
+    if m.test.TestMod(worryLevel) {
+        return m.targets[0]
+    }
+    return m.targets[1]
+}
+
+type MonkeyBuilder struct {
+    id int
+    items []*big.Int
+    operation Expr
+    test Test
+    targets []int
+}
+
+func NewMonkeyBuilder() *MonkeyBuilder {
+    return &MonkeyBuilder{-1, make([]*big.Int, 0), nil, nil, make([]int, 0)}
+}
+
+func (mb *MonkeyBuilder) AddId(id int) {
+    mb.id = id
+}
+
+func (mb *MonkeyBuilder) AddItem(item *big.Int) {
+    mb.items = append(mb.items, item)
+}
+
+func (mb *MonkeyBuilder) AddOperation(operation Expr) {
+    mb.operation = operation
+}
+
+func (mb *MonkeyBuilder) AddTest(test Test) {
+    mb.test = test
+}
+
+func (mb *MonkeyBuilder) AddTarget(target int) {
+    mb.targets = append(mb.targets, target)
+}
+
+// Build creates the monkey from the collected statements. It fails if one
+// of the statements is missing.
+func (mb *MonkeyBuilder) Build() (*Monkey, error) {
+    switch {
+    case mb.id < 0:
+        return nil, errors.New("missing monkey header")
+    case mb.operation == nil:
+        return nil, fmt.Errorf("monkey %d: missing operation", mb.id)
+    case mb.test == nil:
+        return nil, fmt.Errorf("monkey %d: missing test", mb.id)
+    case len(mb.targets) != 2:
+        return nil, fmt.Errorf("monkey %d: expected 2 targets, got %d", mb.id, len(mb.targets))
+    }
+    var targets = [2]int{mb.targets[0], mb.targets[1]}
+    return NewMonkey(mb.id, mb.items, mb.operation, mb.test, targets), nil
+}
+
+func (mb *MonkeyBuilder) Clear() {
+    mb.id = -1
+    mb.items = nil
+    mb.operation = nil
+    mb.test = nil
+    mb.targets = nil
+}
+
+// ---------------------------------- Parser ----------------------------------
+
+type Parser struct {
+    mb *MonkeyBuilder
+    processing, ready bool
+}
+
+func NewParser() *Parser {
+    return &Parser{mb: NewMonkeyBuilder(), processing: false, ready: false}
+}
+
+// Feed parses the next line of a monkey description.
+func (p *Parser) Feed(line string) error {
+    if strings.HasPrefix(line, "Monkey") {
+        if p.processing {
+            return input.Errorf("monkey %d not terminated by an empty line", p.mb.id)
+        }
+        p.processing = true
+        p.ready = false
+        split := input.Split(line, " ")
+        if len(split) != 2 || !strings.HasSuffix(split[1].Text, ":") {
+            return input.Errorf("expected \"Monkey <id>:\"")
+        }
+        field := split[1]
+        field.Text = strings.TrimSuffix(field.Text, ":")
+        id, err := field.Int()
+        if err != nil {
+            return err
+        }
+        p.mb.AddId(id)
+        return nil
+    }
+
+    field := input.Line(line).TrimSpace()
+    if field.Text == "" {
+        if p.processing {
+            p.processing = false
+            p.ready = true
+        }
+        return nil
+    }
+
+    if !p.processing {
+        return field.Errorf("statement outside of a monkey")
+    }
+
+    statement, args, ok := field.Cut(":")
+    if !ok {
+        return field.Errorf("expected \"<statement>: <arguments>\"")
+    }
+    args = args.TrimSpace()
+
+    switch statement.Text {
+    case "Starting items":
+        return p.parseItemStatement(args)
+    case "Operation":
+        return p.parseOperationStatement(args)
+    case "Test":
+        return p.parseTestStatement(args)
+    case "If true":
+        return p.parseIfStatement(args)
+    case "If false":
+        return p.parseIfStatement(args)
+    default:
+        return statement.Errorf("unknown statement")
+    }
+}
+
+func (p *Parser) parseItemStatement(f input.Field) error {
+    if f.Text == "" {
+        return nil
+    }
+    items, err := input.IntList(f, ",")
+    if err != nil {
+        return err
+    }
+    for _, item := range items {
+        p.mb.AddItem(big.NewInt(int64(item)))
+    }
+    return nil
+}
+
+// parseOperationStatement compiles an operation like "new = old * 19",
+// see Expr for the expressions understood.
+func (p *Parser) parseOperationStatement(f input.Field) error {
+    lhs, rhs, ok := f.Cut("=")
+    if !ok || lhs.TrimSpace().Text != "new" {
+        return f.Errorf("expected \"new = <expression>\"")
+    }
+    operation, err := ParseExpr(rhs)
+    if err != nil {
+        return err
+    }
+    p.mb.AddOperation(operation)
+    return nil
+}
+
+func (p *Parser) parseTestStatement(f input.Field) error {
+    statement := f.Fields()
+    if len(statement) != 3 || statement[1].Text != "by" {
+        return f.Errorf("expected \"divisible by <number>\"")
+    }
+    var test Test
+    switch statement[0].Text {
+    case "divisible":
+        tmp, err := statement[2].Int()
+        if err != nil {
+            return err
+        }
+        if tmp <= 0 {
+            return statement[2].Errorf("divisor must be positive")
+        }
+        test = Divisible{big.NewInt(int64(tmp))}
+    default:
+        return statement[0].Errorf("unknown test")
+    }
+    p.mb.AddTest(test)
+    return nil
+}
+
+func (p *Parser) parseIfStatement(f input.Field) error {
+    statement := f.Fields()
+    if len(statement) != 4 || statement[1].Text != "to" || statement[2].Text != "monkey" {
+        return f.Errorf("expected \"throw to monkey <id>\"")
+    }
+    var target int
+    switch statement[0].Text {
+    case "throw":
+        var err error
+        target, err = statement[3].Int()
+        if err != nil {
+            return err
+        }
+    default:
+        return statement[0].Errorf("unknown action")
+    }
+    p.mb.AddTarget(target)
+    return nil
+}
+
+func (p *Parser) Ready() bool {
+    return p.ready
+}
+
+func (p *Parser) GenMonkey() (*Monkey, error) {
+    monkey, err := p.mb.Build()
+    p.mb.Clear()
+    return monkey, err
+}
+
+// ----------------------------------- Main -----------------------------------
+
+func ReadMonkies(r io.Reader) ([]*Monkey, error) {
+    var monkies []*Monkey
+    parser := NewParser()
+
+    err := input.EachLine(r, func(line string) error {
+        if err := parser.Feed(line); err != nil {
+            return err
+        }
+        if parser.Ready() {
+            monkey, err := parser.GenMonkey()
+            if err != nil {
+                return err
+            }
+            monkies = append(monkies, monkey)
+        }
+        return nil
+    })
+
+    if err != nil {
+        return nil, err
+    }
+
+    if parser.processing {
+        monkey, err := parser.GenMonkey()
+        if err != nil {
+            return nil, err
+        }
+        monkies = append(monkies, monkey)
+    }
+
+    if len(monkies) < 2 {
+        return nil, fmt.Errorf("got %d monkies, need at least 2", len(monkies))
+    }
+
+    for i, monkey := range monkies {
+        if monkey.id != i {
+            return nil, fmt.Errorf("monkey %d: expected id %d", monkey.id, i)
+        }
+        for _, target := range monkey.targets {
+            if target < 0 || target >= len(monkies) || target == i {
+                return nil, fmt.Errorf("monkey %d: can't throw to monkey %d", monkey.id, target)
+            }
+        }
+    }
+
+    return monkies, nil
+}
@@ -0,0 +1,548 @@
+package day11
+
+import (
+    "errors"
+    "math/big"
+    "reflect"
+    "strings"
+    "sync"
+    "testing"
+
+    "aoc/input"
+    "aoc/solver/solvertest"
+)
+
+const example = `Monkey 0:
+  Starting items: 79, 98
+  Operation: new = old * 19
+  Test: divisible by 23
+    If true: throw to monkey 2
+    If false: throw to monkey 3
+
+Monkey 1:
+  Starting items: 54, 65, 75, 74
+  Operation: new = old + 6
+  Test: divisible by 19
+    If true: throw to monkey 2
+    If false: throw to monkey 0
+
+Monkey 2:
+  Starting items: 79, 60, 97
+  Operation: new = old * old
+  Test: divisible by 13
+    If true: throw to monkey 1
+    If false: throw to monkey 3
+
+Monkey 3:
+  Starting items: 74
+  Operation: new = old + 3
+  Test: divisible by 17
+    If true: throw to monkey 0
+    If false: throw to monkey 1
+`
+
+func TestExamples(t *testing.T) {
+    for _, name := range EngineNames() {
+        t.Run(name, func(t *testing.T) {
+            solvertest.Run(t, Solver{Engine: name}, []solvertest.Example{
+                {Name: "example", Input: example, Part1: "10605", Part2: "2713310158"},
+            })
+        })
+    }
+}
+
+func TestNegativeRounds(t *testing.T) {
+    for _, name := range EngineNames() {
+        _, err := Solver{Rounds: -5, Engine: name}.Part2(strings.NewReader(example))
+        if err == nil || !strings.Contains(err.Error(), "negative number of rounds") {
+            t.Errorf("%s: Part2 with -5 rounds error = %v", name, err)
+        }
+    }
+
+    troop, err := ReadTroop(strings.NewReader(example), nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := troop.Extrapolate(-5); err == nil {
+        t.Error("Extrapolate(-5) didn't fail")
+    }
+}
+
+func TestMalformedInput(t *testing.T) {
+    tests := []struct {
+        old, new string
+        line, column int
+    }{
+        {"79, 98", "79, x", 2, 23},
+        {"old * 19", "old ^ 19", 3, 24},
+        {"old * 19", "old * y", 3, 26},
+        {"divisible by 23", "divisible by 0", 4, 22},
+        {"throw to monkey 2", "throw monkey 2", 5, 14},
+        {"Test:", "Check:", 4, 3},
+    }
+
+    for _, tt := range tests {
+        in := strings.Replace(example, tt.old, tt.new, 1)
+        _, err := Solver{}.Part1(strings.NewReader(in))
+        var pe *input.ParseError
+        if !errors.As(err, &pe) || pe.Line != tt.line || pe.Column != tt.column {
+            t.Errorf("Part1 with %q error = %v, want parse error at %d:%d", tt.new, err, tt.line, tt.column)
+        }
+    }
+}
+
+func TestInvalidTarget(t *testing.T) {
+    in := strings.Replace(example, "throw to monkey 3", "throw to monkey 7", 1)
+    if _, err := (Solver{}).Part1(strings.NewReader(in)); err == nil {
+        t.Error("Part1 accepted a throw to a missing monkey")
+    }
+}
+
+// huge has divisors whose LCM just fits into an uint64, so that sums and
+// products of worry levels overflow it.
+const huge = `Monkey 0:
+  Starting items: 18446744073709551, 4294967290
+  Operation: new = old * old
+  Test: divisible by 4294967291
+    If true: throw to monkey 1
+    If false: throw to monkey 2
+
+Monkey 1:
+  Starting items: 9223372036854775807
+  Operation: new = old + 9223372036854775783
+  Test: divisible by 4294967279
+    If true: throw to monkey 2
+    If false: throw to monkey 0
+
+Monkey 2:
+  Starting items: 1, 2, 3
+  Operation: new = old * 4294967295
+  Test: divisible by 4294967291
+    If true: throw to monkey 0
+    If false: throw to monkey 1
+`
+
+// bigTroop returns a troop of the monkies described by in reducing the worry
+// levels modulo the LCM of the tests, but keeping them as big integers.
+func bigTroop(t *testing.T, in string) *Troop {
+    monkies, err := ReadMonkies(strings.NewReader(in))
+    if err != nil {
+        t.Fatal(err)
+    }
+    modulus := Modulus(monkies)
+    return NewTroop(monkies, func(worryLevel *big.Int) *big.Int {
+        return worryLevel.Mod(worryLevel, modulus)
+    })
+}
+
+func TestModularTroop(t *testing.T) {
+    for name, in := range map[string]string{"example": example, "huge": huge} {
+        troop, err := ReadTroop(strings.NewReader(in), nil)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if troop.modItems == nil {
+            t.Fatalf("%s: troop doesn't play on uint64", name)
+        }
+        want := bigTroop(t, in)
+
+        for _, rounds := range []int{1, 19, 980} {
+            if err := troop.Run(rounds); err != nil {
+                t.Fatal(err)
+            }
+            if err := want.Run(rounds); err != nil {
+                t.Fatal(err)
+            }
+            if got, want := troop.Snapshot(), want.Snapshot(); !reflect.DeepEqual(got, want) {
+                t.Errorf("%s: after %d rounds got %v, want %v", name, got.Round, got, want)
+            }
+        }
+    }
+}
+
+func TestModMath(t *testing.T) {
+    const m = 1<<64 - 59 // largest prime below 2^64
+    values := []uint64{0, 1, 2, 1 << 32, 1<<63 + 1, m - 2, m - 1}
+    bm := new(big.Int).SetUint64(m)
+    for _, x := range values {
+        for _, y := range values {
+            bx, by := new(big.Int).SetUint64(x), new(big.Int).SetUint64(y)
+            sum := new(big.Int).Add(bx, by)
+            if got, want := AddMod(x, y, m), sum.Mod(sum, bm).Uint64(); got != want {
+                t.Errorf("AddMod(%d, %d) = %d, want %d", x, y, got, want)
+            }
+            prod := new(big.Int).Mul(bx, by)
+            if got, want := MulMod(x, y, m), prod.Mod(prod, bm).Uint64(); got != want {
+                t.Errorf("MulMod(%d, %d) = %d, want %d", x, y, got, want)
+            }
+        }
+    }
+}
+
+func TestBigFallback(t *testing.T) {
+    in := strings.Replace(huge, "divisible by 4294967279", "divisible by 4294967311", 1)
+    troop, err := ReadTroop(strings.NewReader(in), nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if troop.Modulus().IsUint64() {
+        t.Fatal("modulus fits into an uint64")
+    }
+    want := bigTroop(t, in)
+
+    for _, tr := range []*Troop{troop, want} {
+        if err := tr.Run(10000); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if got, want := troop.Business(), want.Business(); got != want {
+        t.Errorf("Business = %d, want %d", got, want)
+    }
+}
+
+func TestSnapshot(t *testing.T) {
+    troop, err := ReadTroop(strings.NewReader(example), DivideBy(3))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := troop.Round(); err != nil {
+        t.Fatal(err)
+    }
+
+    want := [][]int64{{20, 23, 27, 26}, {2080, 25, 167, 207, 401, 1046}, nil, nil}
+    s := troop.Snapshot()
+    for i, items := range s.Items {
+        var got []int64
+        for _, item := range items {
+            got = append(got, item.Int64())
+        }
+        if !reflect.DeepEqual(got, want[i]) {
+            t.Errorf("after round 1 monkey %d holds %v, want %v", i, got, want[i])
+        }
+    }
+
+    // Changing a snapshot must not change the troop.
+    s.Items[0][0].SetInt64(0)
+    s.Inspected[0] = 0
+    if err := troop.Run(19); err != nil {
+        t.Fatal(err)
+    }
+    if got, want := troop.Snapshot().Inspected, []int{101, 95, 7, 105}; !reflect.DeepEqual(got, want) {
+        t.Errorf("after round %d inspected %v, want %v", troop.Rounds(), got, want)
+    }
+}
+
+func TestTroopsSideBySide(t *testing.T) {
+    monkies, err := ReadMonkies(strings.NewReader(example))
+    if err != nil {
+        t.Fatal(err)
+    }
+    // Reading other monkies must not affect the ones read before.
+    if _, err := ReadMonkies(strings.NewReader(huge)); err != nil {
+        t.Fatal(err)
+    }
+
+    var wg sync.WaitGroup
+    results := make([]int, 8)
+    for i := range results {
+        relief := DivideBy(3)
+        rounds := 20
+        if i%2 == 1 {
+            relief, rounds = nil, 10000
+        }
+        wg.Add(1)
+        go func(i int, troop *Troop, rounds int) {
+            defer wg.Done()
+            if err := troop.Run(rounds); err != nil {
+                t.Error(err)
+            }
+            results[i] = troop.Business()
+        }(i, NewTroop(monkies, relief), rounds)
+    }
+    wg.Wait()
+
+    for i, got := range results {
+        want := 10605
+        if i%2 == 1 {
+            want = 2713310158
+        }
+        if got != want {
+            t.Errorf("troop %d: Business = %d, want %d", i, got, want)
+        }
+    }
+}
+
+func TestExpr(t *testing.T) {
+    tests := []struct {
+        expr string
+        old int64
+        want int64
+        str string
+    }{
+        {"old * 19", 2, 38, "old * 19"},
+        {"old + 2 * 3", 1, 7, "old + 2 * 3"},
+        {"(old + 2) * 3", 1, 9, "(old + 2) * 3"},
+        {"old - 2 - 3", 10, 5, "old - 2 - 3"},
+        {"old - (2 - 3)", 10, 11, "old - (2 - 3)"},
+        {"-old * -(old)", 4, 16, "-old * -old"},
+        {"old / 3", -7, -3, "old / 3"},
+        {"old % 3", -7, 2, "old % 3"},
+        {"((old))%(old-1)", 5, 1, "old % (old - 1)"},
+    }
+
+    for _, tt := range tests {
+        e, err := ParseExpr(input.Line(tt.expr))
+        if err != nil {
+            t.Errorf("ParseExpr(%q) error: %v", tt.expr, err)
+            continue
+        }
+        got, err := e.Eval(big.NewInt(tt.old))
+        if err != nil || got.Int64() != tt.want {
+            t.Errorf("%q with old = %d: got %v, %v, want %d", tt.expr, tt.old, got, err, tt.want)
+        }
+        if e.String() != tt.str {
+            t.Errorf("ParseExpr(%q).String() = %q, want %q", tt.expr, e, tt.str)
+        }
+    }
+}
+
+func TestExprMod(t *testing.T) {
+    const m = 1<<64 - 59
+    for _, expr := range []string{"old * old + 7", "-(old - 3) * (old + 1)", "old - old * 2"} {
+        e, err := ParseExpr(input.Line(expr))
+        if err != nil {
+            t.Fatal(err)
+        }
+        f := e.mod(m)
+        for _, old := range []uint64{0, 1, 2, 1 << 40, m - 1} {
+            want, err := e.Eval(new(big.Int).SetUint64(old))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got := f(old); got != reduce(want, m) {
+                t.Errorf("%q mod m with old = %d = %d, want %d", expr, old, got, reduce(want, m))
+            }
+        }
+    }
+}
+
+func TestExprErrors(t *testing.T) {
+    tests := []struct {
+        expr string
+        column int
+    }{
+        {"old +", 6},
+        {"old old", 5},
+        {"(old + 1", 9},
+        {"old ^ 2", 5},
+        {"old / 0", 5},
+        {"x * 2", 1},
+        {")", 1},
+    }
+
+    for _, tt := range tests {
+        _, err := ParseExpr(input.Line(tt.expr))
+        var pe *input.ParseError
+        if !errors.As(err, &pe) || pe.Column != tt.column {
+            t.Errorf("ParseExpr(%q) error = %v, want parse error at column %d", tt.expr, err, tt.column)
+        }
+    }
+
+    e, err := ParseExpr(input.Line("old / (old - 1)"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := e.Eval(big.NewInt(1)); !errors.Is(err, ErrDivisionByZero) {
+        t.Errorf("Eval dividing by zero error = %v, want %v", err, ErrDivisionByZero)
+    }
+}
+
+func TestExtendedOperations(t *testing.T) {
+    // Both variants compute the same worry levels as the example.
+    modular := strings.NewReplacer(
+        "old * 19", "(old - 1) * 19 + 19",
+        "old + 6", "old - -6",
+        "old * old", "old * (old + 0)",
+        "old + 3", "-(-old - 3)",
+    ).Replace(example)
+    dividing := strings.NewReplacer(
+        "old * 19", "old * 38 / 2",
+        "old + 3", "(old + 3) % (old + 4)",
+    ).Replace(example)
+
+    solvertest.Run(t, Solver{}, []solvertest.Example{
+        {Name: "modular", Input: modular, Part1: "10605", Part2: "2713310158"},
+        {Name: "dividing", Input: dividing, Part1: "10605"},
+    })
+
+    in := strings.Replace(example, "old + 3", "old / (old - 74)", 1)
+    if _, err := (Solver{}).Part1(strings.NewReader(in)); !errors.Is(err, ErrDivisionByZero) {
+        t.Errorf("Part1 dividing by zero error = %v, want %v", err, ErrDivisionByZero)
+    }
+}
+
+func TestExtrapolate(t *testing.T) {
+    for name, in := range map[string]string{"example": example, "huge": huge} {
+        troop, err := ReadTroop(strings.NewReader(in), nil)
+        if err != nil {
+            t.Fatal(err)
+        }
+        played := 0
+        for _, rounds := range []int{0, 1, 20, 1000, 10000} {
+            got, err := troop.Extrapolate(rounds - played)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if err := troop.Run(rounds - played); err != nil {
+                t.Fatal(err)
+            }
+            played = rounds
+            if want := troop.Snapshot().Inspected; !reflect.DeepEqual(got, want) {
+                t.Errorf("%s: extrapolated %d rounds to %v, want %v", name, rounds, got, want)
+            }
+        }
+    }
+
+    troop := bigTroop(t, example)
+    if _, err := troop.Extrapolate(10); err != ErrNotModular {
+        t.Errorf("Extrapolate on big integers error = %v, want %v", err, ErrNotModular)
+    }
+}
+
+func TestTrajectory(t *testing.T) {
+    troop, err := ReadTroop(strings.NewReader(example), nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // Item 2 is the first one of monkey 1.
+    tr, err := troop.Trajectory(2, 1000000)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if tr.Monkey != 1 || tr.WorryLevel != 54 {
+        t.Errorf("item 2 starts at monkey %d with %d, want monkey 1 with 54", tr.Monkey, tr.WorryLevel)
+    }
+    if tr.Cycle < 0 {
+        t.Fatal("no cycle within 10^6 rounds")
+    }
+    want := []Inspection{{1, 60, 0}}
+    if !reflect.DeepEqual(tr.Rounds[0], want) {
+        t.Errorf("first round %v, want %v", tr.Rounds[0], want)
+    }
+
+    // Another pass through the cycle adds the inspections of its rounds.
+    length := len(tr.Rounds) - tr.Cycle
+    once, twice := make([]int, 4), make([]int, 4)
+    tr.Inspected(tr.Cycle+length+1, once)
+    tr.Inspected(tr.Cycle+2*length+1, twice)
+    cycle := make([]int, 4)
+    for _, inspections := range tr.Rounds[tr.Cycle:] {
+        for _, in := range inspections {
+            cycle[in.Monkey]++
+        }
+    }
+    for i := range once {
+        if twice[i]-once[i] != cycle[i] {
+            t.Errorf("monkey %d inspects %d times more in another cycle, want %d", i, twice[i]-once[i], cycle[i])
+        }
+    }
+
+    var sb strings.Builder
+    tr.Format(&sb, 1)
+    if !strings.HasPrefix(sb.String(), "starts at monkey 1 with worry level 54\nround 1: monkey 1: 60 -> monkey 0\n") {
+        t.Errorf("Format wrote\n%s", sb.String())
+    }
+
+    if _, err := troop.Trajectory(10, 100); err == nil {
+        t.Error("Trajectory of a missing item succeeded")
+    }
+}
+
+func TestManyRounds(t *testing.T) {
+    var log strings.Builder
+    s := Solver{Rounds: 1000000000, Trace: []int{0}, Log: &log}
+    got, err := s.Part2(strings.NewReader(example))
+    if err != nil {
+        t.Fatal(err)
+    }
+    troop, err := ReadTroop(strings.NewReader(example), nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    inspected, err := troop.Extrapolate(1000000000)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := MonkeyBusiness(inspected).String(); string(got) != want || len(want) < 19 {
+        t.Errorf("Part2 after 10^9 rounds = %s, want %s", got, want)
+    }
+    if !strings.HasPrefix(log.String(), "item 0 starts at monkey 0 with worry level 79\n") {
+        t.Errorf("trace of item 0:\n%s", log.String())
+    }
+}
+
+func TestParseTrace(t *testing.T) {
+    if n, err := parseTrace("item=12"); n != 12 || err != nil {
+        t.Errorf("parseTrace(\"item=12\") = %d, %v", n, err)
+    }
+    for _, value := range []string{"12", "monkey=1", "item=", "item=-1"} {
+        if _, err := parseTrace(value); err == nil {
+            t.Errorf("parseTrace(%q) succeeded", value)
+        }
+    }
+}
+
+// TestConcurrent compares the concurrent engine with the sequential one.
+// Run it with -race.
+func TestConcurrent(t *testing.T) {
+    // Divided by 3 the worry levels still grow, so the game with relief
+    // is only played for the 20 rounds of part 1.
+    tests := []struct {
+        name, in string
+        relief Relief
+        rounds []int
+    }{
+        {"relief", example, DivideBy(3), []int{0, 1, 19}},
+        {"uint64", example, nil, []int{0, 1, 19, 480}},
+        {"huge", huge, nil, []int{0, 1, 19, 480}},
+        {"big", strings.Replace(huge, "divisible by 4294967279", "divisible by 4294967311", 1), nil, []int{1, 99}},
+    }
+
+    for _, tt := range tests {
+        monkies, err := ReadMonkies(strings.NewReader(tt.in))
+        if err != nil {
+            t.Fatal(err)
+        }
+        sequential, concurrent := NewTroop(monkies, tt.relief), NewTroop(monkies, tt.relief)
+        for _, rounds := range tt.rounds {
+            if err := sequential.Run(rounds); err != nil {
+                t.Fatal(err)
+            }
+            if err := concurrent.RunConcurrent(rounds); err != nil {
+                t.Fatal(err)
+            }
+            if got, want := concurrent.Snapshot(), sequential.Snapshot(); !reflect.DeepEqual(got, want) {
+                t.Errorf("%s: after %d rounds got %v, want %v", tt.name, got.Round, got, want)
+            }
+        }
+    }
+}
+
+func TestConcurrentError(t *testing.T) {
+    in := strings.Replace(example, "old + 3", "old / (old - 74)", 1)
+    monkies, err := ReadMonkies(strings.NewReader(in))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    sequential, concurrent := NewTroop(monkies, DivideBy(3)), NewTroop(monkies, DivideBy(3))
+    want := sequential.Run(5)
+    got := concurrent.RunConcurrent(5)
+    if got == nil || want == nil || got.Error() != want.Error() || !errors.Is(got, ErrDivisionByZero) {
+        t.Errorf("RunConcurrent error = %v, want %v", got, want)
+    }
+    if got, want := concurrent.Snapshot(), sequential.Snapshot(); !reflect.DeepEqual(got, want) {
+        t.Errorf("after error got %v, want %v", got, want)
+    }
+}
@@ -0,0 +1,317 @@
+package day11
+
+import (
+    "errors"
+    "fmt"
+    "math/big"
+    "strings"
+
+    "aoc/input"
+)
+
+// ErrDivisionByZero is returned when an operation divides by zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// ------------------------------- Expressions --------------------------------
+
+// Expr is the compiled right hand side of an operation like "new = old * 19".
+// Besides the integers and old it supports +, -, *, / and % with the usual
+// precedence, unary minus and parentheses. / and % are the Euclidean
+// division and modulus, so the remainder is never negative.
+type Expr interface {
+    // Eval computes the new worry level. It doesn't modify old.
+    Eval(old *big.Int) (*big.Int, error)
+    // Modular reports whether the value modulo any m only depends on old
+    // modulo m, i.e. whether the expression neither divides nor takes a
+    // remainder.
+    Modular() bool
+    String() string
+
+    // mod compiles the expression for worry levels reduced modulo m. It is
+    // only called for modular expressions.
+    mod(m uint64) func(old uint64) uint64
+    precedence() int
+}
+
+// Old is the current worry level.
+type Old struct{}
+
+// Num is an integer constant.
+type Num struct {
+    N *big.Int
+}
+
+// Neg negates X.
+type Neg struct {
+    X Expr
+}
+
+// Binary applies the operator Op, one of "+-*/%", to X and Y.
+type Binary struct {
+    Op byte
+    X, Y Expr
+}
+
+func (Old) Eval(old *big.Int) (*big.Int, error) {
+    return old, nil
+}
+
+func (n Num) Eval(*big.Int) (*big.Int, error) {
+    return new(big.Int).Set(n.N), nil
+}
+
+func (n Neg) Eval(old *big.Int) (*big.Int, error) {
+    x, err := n.X.Eval(old)
+    if err != nil {
+        return nil, err
+    }
+    return new(big.Int).Neg(x), nil
+}
+
+func (b Binary) Eval(old *big.Int) (*big.Int, error) {
+    x, err := b.X.Eval(old)
+    if err != nil {
+        return nil, err
+    }
+    y, err := b.Y.Eval(old)
+    if err != nil {
+        return nil, err
+    }
+
+    result := new(big.Int)
+    switch b.Op {
+    case '+':
+        result.Add(x, y)
+    case '-':
+        result.Sub(x, y)
+    case '*':
+        result.Mul(x, y)
+    case '/', '%':
+        if y.Sign() == 0 {
+            return nil, ErrDivisionByZero
+        }
+        if b.Op == '/' {
+            result.Div(x, y)
+        } else {
+            result.Mod(x, y)
+        }
+    default:
+        panic("unreachable line")
+    }
+    return result, nil
+}
+
+func (Old) Modular() bool { return true }
+func (Num) Modular() bool { return true }
+func (n Neg) Modular() bool { return n.X.Modular() }
+
+func (b Binary) Modular() bool {
+    return b.Op != '/' && b.Op != '%' && b.X.Modular() && b.Y.Modular()
+}
+
+func (Old) mod(uint64) func(uint64) uint64 {
+    return func(old uint64) uint64 { return old }
+}
+
+func (n Num) mod(m uint64) func(uint64) uint64 {
+    v := reduce(n.N, m)
+    return func(uint64) uint64 { return v }
+}
+
+func (n Neg) mod(m uint64) func(uint64) uint64 {
+    x := n.X.mod(m)
+    return func(old uint64) uint64 {
+        return (m - x(old)) % m
+    }
+}
+
+func (b Binary) mod(m uint64) func(uint64) uint64 {
+    x, y := b.X.mod(m), b.Y.mod(m)
+    switch b.Op {
+    case '+':
+        return func(old uint64) uint64 { return AddMod(x(old), y(old), m) }
+    case '-':
+        return func(old uint64) uint64 { return AddMod(x(old), m-y(old), m) }
+    case '*':
+        return func(old uint64) uint64 { return MulMod(x(old), y(old), m) }
+    default:
+        panic(fmt.Sprintf("day11: %v is not modular", b))
+    }
+}
+
+func (Old) precedence() int { return 4 }
+func (Num) precedence() int { return 4 }
+func (Neg) precedence() int { return 3 }
+func (b Binary) precedence() int { return precedences[b.Op] }
+
+var precedences = map[byte]int{'+': 1, '-': 1, '*': 2, '/': 2, '%': 2}
+
+func (Old) String() string { return "old" }
+func (n Num) String() string { return n.N.String() }
+
+func (n Neg) String() string {
+    return "-" + parenthesize(n.X, n.precedence())
+}
+
+// String prints the expression with the parentheses it needs. Since none
+// of the operators is right associative, the right operand is enclosed in
+// parentheses already if its precedence equals the one of b.
+func (b Binary) String() string {
+    p := b.precedence()
+    return parenthesize(b.X, p) + " " + string(b.Op) + " " + parenthesize(b.Y, p+1)
+}
+
+func parenthesize(e Expr, precedence int) string {
+    if e.precedence() < precedence {
+        return "(" + e.String() + ")"
+    }
+    return e.String()
+}
+
+// --------------------------------- Parsing ----------------------------------
+
+// ParseExpr compiles an expression like "(old + 3) * old".
+func ParseExpr(f input.Field) (Expr, error) {
+    tokens, err := tokenize(f)
+    if err != nil {
+        return nil, err
+    }
+    p := &exprParser{tokens: tokens, end: input.Field{Column: f.Column + len(f.Text)}}
+    e, err := p.sum()
+    if err != nil {
+        return nil, err
+    }
+    if tok := p.peek(); tok.Text != "" {
+        return nil, tok.Errorf("unexpected %q", tok.Text)
+    }
+    return e, nil
+}
+
+// tokenize splits f into integers, names, operators and parentheses.
+func tokenize(f input.Field) ([]input.Field, error) {
+    var tokens []input.Field
+    for i := 0; i < len(f.Text); {
+        start := i
+        switch c := f.Text[i]; {
+        case c == ' ' || c == '\t':
+            i++
+            continue
+        case isDigit(c):
+            for i < len(f.Text) && isDigit(f.Text[i]) {
+                i++
+            }
+        case isLetter(c):
+            for i < len(f.Text) && (isLetter(f.Text[i]) || isDigit(f.Text[i])) {
+                i++
+            }
+        case strings.IndexByte("+-*/%()", c) >= 0:
+            i++
+        default:
+            tok := input.Field{Text: f.Text[i : i+1], Column: f.Column + i}
+            return nil, tok.Errorf("unexpected character")
+        }
+        tokens = append(tokens, input.Field{Text: f.Text[start:i], Column: f.Column + start})
+    }
+    return tokens, nil
+}
+
+func isDigit(c byte) bool {
+    return '0' <= c && c <= '9'
+}
+
+func isLetter(c byte) bool {
+    return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_'
+}
+
+type exprParser struct {
+    tokens []input.Field
+    pos int
+    end input.Field
+}
+
+// peek returns the next token, an empty one at the end of the expression.
+func (p *exprParser) peek() input.Field {
+    if p.pos >= len(p.tokens) {
+        return p.end
+    }
+    return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() input.Field {
+    tok := p.peek()
+    if p.pos < len(p.tokens) {
+        p.pos++
+    }
+    return tok
+}
+
+// sum parses terms separated by + and -.
+func (p *exprParser) sum() (Expr, error) {
+    return p.binary("+-", p.product)
+}
+
+// product parses factors separated by *, / and %.
+func (p *exprParser) product() (Expr, error) {
+    return p.binary("*/%", p.factor)
+}
+
+func (p *exprParser) binary(ops string, operand func() (Expr, error)) (Expr, error) {
+    x, err := operand()
+    if err != nil {
+        return nil, err
+    }
+    for {
+        tok := p.peek()
+        if len(tok.Text) != 1 || strings.IndexByte(ops, tok.Text[0]) < 0 {
+            return x, nil
+        }
+        p.next()
+        y, err := operand()
+        if err != nil {
+            return nil, err
+        }
+        if tok.Text[0] == '/' || tok.Text[0] == '%' {
+            if n, ok := y.(Num); ok && n.N.Sign() == 0 {
+                return nil, tok.Errorf("division by zero")
+            }
+        }
+        x = Binary{tok.Text[0], x, y}
+    }
+}
+
+// factor parses an integer, old, a negated factor or a parenthesized
+// expression.
+func (p *exprParser) factor() (Expr, error) {
+    tok := p.next()
+    switch {
+    case tok.Text == "":
+        return nil, tok.Errorf("unexpected end of expression")
+    case tok.Text == "-":
+        x, err := p.factor()
+        if err != nil {
+            return nil, err
+        }
+        return Neg{x}, nil
+    case tok.Text == "(":
+        x, err := p.sum()
+        if err != nil {
+            return nil, err
+        }
+        if closing := p.next(); closing.Text != ")" {
+            return nil, closing.Errorf("expected ')'")
+        }
+        return x, nil
+    case tok.Text == "old":
+        return Old{}, nil
+    case isDigit(tok.Text[0]):
+        n, ok := new(big.Int).SetString(tok.Text, 10)
+        if !ok {
+            return nil, tok.Errorf("invalid integer")
+        }
+        return Num{n}, nil
+    case isLetter(tok.Text[0]):
+        return nil, tok.Errorf("unknown variable")
+    default:
+        return nil, tok.Errorf("expected integer, old or '('")
+    }
+}
@@ -0,0 +1,52 @@
+package day11
+
+import (
+    "math/big"
+    "math/bits"
+)
+
+// --------------------------------- Modulus ----------------------------------
+
+// Modulus returns the least common multiple of the moduli of the monkies'
+// tests. Reducing worry levels modulo it changes neither the outcome of a
+// test nor, as long as all operations are Modular, any later worry level
+// modulo it.
+func Modulus(monkies []*Monkey) *big.Int {
+    lcm := big.NewInt(1)
+    for _, monkey := range monkies {
+        m := monkey.test.Modulus()
+        var gcd big.Int
+        gcd.GCD(nil, nil, lcm, m)
+        lcm.Mul(lcm, m)
+        lcm.Div(lcm, &gcd)
+    }
+    return lcm
+}
+
+// AddMod returns (x + y) mod m for x, y < m without overflowing.
+func AddMod(x, y, m uint64) uint64 {
+    sum, carry := bits.Add64(x, y, 0)
+    return bits.Rem64(carry, sum, m)
+}
+
+// MulMod returns (x * y) mod m, computing the full 128 bit product.
+func MulMod(x, y, m uint64) uint64 {
+    hi, lo := bits.Mul64(x, y)
+    return bits.Rem64(hi, lo, m)
+}
+
+// reduce returns n mod m as uint64.
+func reduce(n *big.Int, m uint64) uint64 {
+    var r big.Int
+    return r.Mod(n, new(big.Int).SetUint64(m)).Uint64()
+}
+
+// Modular reports whether all operations of the monkies are Modular.
+func Modular(monkies []*Monkey) bool {
+    for _, monkey := range monkies {
+        if !monkey.operation.Modular() {
+            return false
+        }
+    }
+    return true
+}
@@ -0,0 +1,198 @@
+package day11
+
+import (
+    "fmt"
+    "io"
+    "math/big"
+)
+
+// ---------------------------------- Troop -----------------------------------
+
+// Relief adjusts the worry level of an item after the monkey's operation
+// was applied. It may modify and return its argument.
+type Relief func(worryLevel *big.Int) *big.Int
+
+// DivideBy returns the relief of part 1, dividing the worry level by n.
+func DivideBy(n int) Relief {
+    d := big.NewInt(int64(n))
+    return func(worryLevel *big.Int) *big.Int {
+        return worryLevel.Div(worryLevel, d)
+    }
+}
+
+// Troop plays the game of a group of monkies. It owns all state of the
+// game, so troops of the same monkies may play side by side.
+//
+// Without relief the worry levels are reduced modulo the LCM of the tests
+// as long as all operations are Modular, using uint64 worry levels if the
+// LCM fits.
+type Troop struct {
+    monkies []*Monkey
+    relief Relief
+    modulus *big.Int
+    round int
+    inspected []int
+
+    // The items each monkey holds, either as big integers or reduced
+    // modulo the modulus when playing on uint64.
+    items [][]*big.Int
+    modItems [][]uint64
+    operations []func(uint64) uint64
+}
+
+// NewTroop starts a game of the monkies as returned by ReadMonkies. A nil
+// relief keeps the worry levels.
+func NewTroop(monkies []*Monkey, relief Relief) *Troop {
+    t := &Troop{monkies: monkies, relief: relief, inspected: make([]int, len(monkies))}
+
+    if relief == nil && Modular(monkies) {
+        t.modulus = Modulus(monkies)
+    }
+
+    if t.modulus != nil && t.modulus.IsUint64() {
+        m := t.modulus.Uint64()
+        t.modItems = make([][]uint64, len(monkies))
+        t.operations = make([]func(uint64) uint64, len(monkies))
+        for i, monkey := range monkies {
+            for _, item := range monkey.items {
+                t.modItems[i] = append(t.modItems[i], reduce(item, m))
+            }
+            t.operations[i] = monkey.operation.mod(m)
+        }
+        return t
+    }
+
+    t.items = make([][]*big.Int, len(monkies))
+    for i, monkey := range monkies {
+        for _, item := range monkey.items {
+            t.items[i] = append(t.items[i], new(big.Int).Set(item))
+        }
+    }
+    return t
+}
+
+// ReadTroop reads the monkies from r and starts a game of them.
+func ReadTroop(r io.Reader, relief Relief) (*Troop, error) {
+    monkies, err := ReadMonkies(r)
+    if err != nil {
+        return nil, err
+    }
+    return NewTroop(monkies, relief), nil
+}
+
+// Modulus returns the number the worry levels are reduced by, nil if they
+// are not reduced.
+func (t *Troop) Modulus() *big.Int {
+    return t.modulus
+}
+
+// Rounds returns the number of rounds played.
+func (t *Troop) Rounds() int {
+    return t.round
+}
+
+// Round lets every monkey inspect and throw all the items it holds, one
+// monkey after the other.
+func (t *Troop) Round() error {
+    for i := range t.monkies {
+        var err error
+        if t.modItems != nil {
+            err = turn(t.modItems, t.inspected, i, t.inspectMod)
+        } else {
+            err = turn(t.items, t.inspected, i, t.inspectBig)
+        }
+        if err != nil {
+            return fmt.Errorf("round %d: %w", t.round+1, err)
+        }
+    }
+    t.round++
+    return nil
+}
+
+// Run plays n rounds.
+func (t *Troop) Run(n int) error {
+    for i := 0; i < n; i++ {
+        if err := t.Round(); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// inspectFunc lets monkey i inspect an item. It returns the new worry level
+// of the item and the monkey it is thrown to.
+type inspectFunc[T any] func(i int, worryLevel T) (T, int, error)
+
+func (t *Troop) inspectMod(i int, worryLevel uint64) (uint64, int, error) {
+    worryLevel = t.operations[i](worryLevel)
+    return worryLevel, t.monkies[i].throwMod(worryLevel), nil
+}
+
+func (t *Troop) inspectBig(i int, worryLevel *big.Int) (*big.Int, int, error) {
+    monkey := t.monkies[i]
+    worryLevel, err := monkey.operation.Eval(worryLevel)
+    if err != nil {
+        return nil, 0, fmt.Errorf("monkey %d: new = %v: %w", monkey.id, monkey.operation, err)
+    }
+
+    switch {
+    case t.relief != nil:
+        worryLevel = t.relief(worryLevel)
+    case t.modulus != nil:
+        worryLevel = worryLevel.Mod(worryLevel, t.modulus)
+    }
+    return worryLevel, monkey.Throw(worryLevel), nil
+}
+
+// turn lets monkey i inspect and throw the items it holds. On error it
+// keeps the items not inspected yet.
+func turn[T any](items [][]T, inspected []int, i int, inspect inspectFunc[T]) error {
+    for j, worryLevel := range items[i] {
+        worryLevel, target, err := inspect(i, worryLevel)
+        if err != nil {
+            items[i] = items[i][j:]
+            return err
+        }
+        inspected[i]++
+        items[target] = append(items[target], worryLevel)
+    }
+    items[i] = items[i][:0]
+    return nil
+}
+
+// Snapshot is the state of a game after some rounds.
+type Snapshot struct {
+    Round int
+    // Items holds the worry levels of the items each monkey holds, reduced
+    // if the troop reduces them.
+    Items [][]*big.Int
+    // Inspected holds the number of items each monkey inspected.
+    Inspected []int
+}
+
+// Snapshot returns a copy of the current state of the game.
+func (t *Troop) Snapshot() Snapshot {
+    s := Snapshot{
+        Round: t.round,
+        Items: make([][]*big.Int, len(t.monkies)),
+        Inspected: append([]int(nil), t.inspected...),
+    }
+    for i := range t.monkies {
+        if t.modItems != nil {
+            for _, item := range t.modItems[i] {
+                s.Items[i] = append(s.Items[i], new(big.Int).SetUint64(item))
+            }
+        } else {
+            for _, item := range t.items[i] {
+                s.Items[i] = append(s.Items[i], new(big.Int).Set(item))
+            }
+        }
+    }
+    return s
+}
+
+// Business returns the product of the inspection counts of the two most
+// active monkies.
+func (t *Troop) Business() int {
+    return int(MonkeyBusiness(t.inspected).Int64())
+}
@@ -0,0 +1,144 @@
+// Package day12 solves "Hill Climbing Algorithm".
+package day12
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"aoc/geom"
+	"aoc/grid"
+	"aoc/input"
+	"aoc/solver"
+)
+
+func init() {
+	solver.Register(12, &Solver{Algo: DefaultAlgo})
+}
+
+// Solver finds the shortest paths with the search algorithm named Algo, see
+// Algos. An empty Algo selects DefaultAlgo. If Render is set, the paths are
+// drawn by RenderPath to Out, os.Stdout if nil.
+type Solver struct {
+	Algo   string
+	Render bool
+	Out    io.Writer
+}
+
+func (s *Solver) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&s.Algo, "algo", s.Algo, "search algorithm: "+strings.Join(AlgoNames(), ", "))
+	fs.BoolVar(&s.Render, "render", s.Render, "draw the shortest paths on the heightmap")
+}
+
+// ShortestPath returns the shortest path from one of the sources to end,
+// both included.
+func (s *Solver) ShortestPath(heightmap *Heightmap, sources []geom.Point, end geom.Point) ([]geom.Point, error) {
+	name := s.Algo
+	if name == "" {
+		name = DefaultAlgo
+	}
+	search, ok := Algos[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown algorithm %q", name)
+	}
+
+	path, ok := search(heightmap, sources, end)
+	if !ok {
+		return nil, errors.New("no path to E")
+	}
+
+	if s.Render {
+		out := s.Out
+		if out == nil {
+			out = os.Stdout
+		}
+		if _, err := io.WriteString(out, RenderPath(heightmap, path)); err != nil {
+			return nil, err
+		}
+	}
+	return path, nil
+}
+
+// Heightmap holds the elevation of every square, 0 for 'a' up to 25 for
+// 'z'.
+type Heightmap = grid.Grid[int]
+
+// CanClimb reports whether one can step from p to its neighbour q, which is
+// at most one higher than p.
+func CanClimb(heightmap *Heightmap, p, q geom.Point) bool {
+	return heightmap.At(q)-heightmap.At(p) <= 1
+}
+
+// ReadHeightmap reads the heightmap from r together with the start and the
+// end position.
+func ReadHeightmap(r io.Reader) (*Heightmap, geom.Point, geom.Point, error) {
+	var start, end geom.Point
+	foundStart, foundEnd := false, false
+
+	heightmap, err := grid.Parse(r, func(p geom.Point, c rune) (int, error) {
+		switch {
+		case c == 'S':
+			if foundStart {
+				return 0, input.Errorf("second start position")
+			}
+			foundStart = true
+			start = p
+			return 0, nil
+		case c == 'E':
+			if foundEnd {
+				return 0, input.Errorf("second end position")
+			}
+			foundEnd = true
+			end = p
+			return 25, nil
+		case 'a' <= c && c <= 'z':
+			return int(c - 'a'), nil
+		default:
+			return 0, input.Errorf("invalid height")
+		}
+	})
+
+	if err != nil {
+		return nil, start, end, err
+	}
+	if !foundStart {
+		return nil, start, end, errors.New("missing start position S")
+	}
+	if !foundEnd {
+		return nil, start, end, errors.New("missing end position E")
+	}
+
+	return heightmap, start, end, nil
+}
+
+// RenderPath draws the heightmap with the path marked by arrows pointing
+// to the next step and E at its end.
+func RenderPath(heightmap *Heightmap, path []geom.Point) string {
+	marks := make(map[geom.Point]rune)
+	for i, p := range path {
+		if i == len(path)-1 {
+			marks[p] = 'E'
+			break
+		}
+		switch path[i+1].Sub(p) {
+		case geom.Up.Step():
+			marks[p] = '^'
+		case geom.Right.Step():
+			marks[p] = '>'
+		case geom.Down.Step():
+			marks[p] = 'v'
+		case geom.Left.Step():
+			marks[p] = '<'
+		}
+	}
+
+	return heightmap.Render(func(p geom.Point, height int) rune {
+		if mark, ok := marks[p]; ok {
+			return mark
+		}
+		return '.'
+	})
+}
@@ -0,0 +1,75 @@
+package day12
+
+import (
+	"strings"
+	"testing"
+
+	"aoc/geom"
+	"aoc/solver/solvertest"
+)
+
+const example = `Sabqponm
+abcryxxl
+accszExk
+acctuvwj
+abdefghi
+`
+
+func TestExamples(t *testing.T) {
+	for _, algo := range AlgoNames() {
+		solvertest.Run(t, &Solver{Algo: algo}, []solvertest.Example{
+			{Name: "example/" + algo, Input: example, Part1: "31", Part2: "29"},
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	heightmap, start, end, err := ReadHeightmap(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, search := range Algos {
+		path, ok := search(heightmap, []geom.Point{start}, end)
+		if !ok || path[0] != start || path[len(path)-1] != end {
+			t.Fatalf("%s: path %v does not lead from S to E", name, path)
+		}
+		for i := 1; i < len(path); i++ {
+			if geom.Manhattan(path[i-1], path[i]) != 1 || !CanClimb(heightmap, path[i-1], path[i]) {
+				t.Errorf("%s: invalid step from %v to %v", name, path[i-1], path[i])
+			}
+		}
+	}
+
+	path, _ := ReverseBFS(heightmap, []geom.Point{start}, end)
+	want := `v..v<<<<
+>v.vv<<^
+.>vv>E^^
+..v>>>^^
+..>>>>>^
+`
+	// the puzzle description shows one of several shortest paths
+	if got := RenderPath(heightmap, path); strings.Count(got, ".") != strings.Count(want, ".") {
+		t.Errorf("RenderPath =\n%s\nwant a path as long as\n%s", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	var out strings.Builder
+	s := &Solver{Render: true, Out: &out}
+	if _, err := s.Part2(strings.NewReader(example)); err != nil {
+		t.Fatal(err)
+	}
+	// 29 steps from the a in the bottom left corner
+	rows := strings.Split(out.String(), "\n")
+	if len(rows) != 6 || strings.Count(out.String(), ".") != 40-30 || !strings.Contains(rows[2], "E") {
+		t.Errorf("rendered path =\n%s", out.String())
+	}
+}
+
+func TestNoPath(t *testing.T) {
+	_, err := (&Solver{}).Part1(strings.NewReader("Sz\nzE\n"))
+	if err == nil {
+		t.Error("Part1 found a path across a cliff")
+	}
+}
@@ -0,0 +1,157 @@
+package day12
+
+import (
+	"sort"
+
+	"aoc/geom"
+	"aoc/graph"
+)
+
+// DefaultAlgo is the search algorithm used unless another one is selected.
+const DefaultAlgo = "bfs"
+
+// Search returns the shortest path from one of the sources to end, both
+// included, and whether there is one.
+type Search func(heightmap *Heightmap, sources []geom.Point, end geom.Point) ([]geom.Point, bool)
+
+// Algos maps the names accepted by the -algo flag to the searches.
+var Algos = map[string]Search{
+	"bfs":          ReverseBFS,
+	"bellman-ford": BellmanFordSearch,
+	"dijkstra":     Dijkstra,
+	"astar":        AStar,
+}
+
+// AlgoNames returns the names of Algos in alphabetical order.
+func AlgoNames() []string {
+	var names []string
+	for name := range Algos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ---------------------------------- Graph -----------------------------------
+
+// Vertex returns the number of the vertex of p in the graph built by
+// BuildGraph.
+func Vertex(heightmap *Heightmap, p geom.Point) int {
+	return p.X + p.Y*heightmap.Width
+}
+
+// Point returns the position of vertex v of the graph built by BuildGraph.
+func Point(heightmap *Heightmap, v int) geom.Point {
+	return geom.Pt(v%heightmap.Width, v/heightmap.Width)
+}
+
+// BuildGraph connects every position of the heightmap with its neighbours.
+// Every step has weight 1, Climbable selects the steps one can take.
+func BuildGraph(heightmap *Heightmap) *graph.Graph {
+	g := graph.New(heightmap.Width * heightmap.Height)
+	heightmap.Each(func(p geom.Point, _ int) {
+		for _, q := range heightmap.Neighbours4(p) {
+			g.AddEdge(Vertex(heightmap, p), Vertex(heightmap, q), 1)
+		}
+	})
+	return g
+}
+
+// Climbable allows the edges of BuildGraph leading at most one step up.
+func Climbable(heightmap *Heightmap) graph.Filter {
+	return func(e graph.Edge) bool {
+		return CanClimb(heightmap, Point(heightmap, e.From), Point(heightmap, e.To))
+	}
+}
+
+func vertices(heightmap *Heightmap, ps []geom.Point) []int {
+	vs := make([]int, len(ps))
+	for i, p := range ps {
+		vs[i] = Vertex(heightmap, p)
+	}
+	return vs
+}
+
+func points(heightmap *Heightmap, vs []int) []geom.Point {
+	ps := make([]geom.Point, len(vs))
+	for i, v := range vs {
+		ps[i] = Point(heightmap, v)
+	}
+	return ps
+}
+
+// -------------------------------- Searches ----------------------------------
+
+// ReverseBFS walks down from end, visiting every square at most once. As
+// every step costs the same, the distance of each source is its number of
+// steps to end, so a single pass finds the nearest of any number of
+// sources.
+func ReverseBFS(heightmap *Heightmap, sources []geom.Point, end geom.Point) ([]geom.Point, bool) {
+	climbable := Climbable(heightmap)
+	descendable := func(e graph.Edge) bool {
+		return climbable(graph.Edge{From: e.To, To: e.From, Weight: e.Weight})
+	}
+	paths := BuildGraph(heightmap).Reverse().BFS(descendable, Vertex(heightmap, end))
+
+	nearest := -1
+	for _, v := range vertices(heightmap, sources) {
+		if paths.Reachable(v) && (nearest == -1 || paths.Dist[v] < paths.Dist[nearest]) {
+			nearest = v
+		}
+	}
+	if nearest == -1 {
+		return nil, false
+	}
+
+	path := points(heightmap, paths.PathTo(nearest))
+	reverse(path)
+	return path, true
+}
+
+// BellmanFordSearch relaxes all edges until the distances from the sources
+// settle.
+func BellmanFordSearch(heightmap *Heightmap, sources []geom.Point, end geom.Point) ([]geom.Point, bool) {
+	paths, err := BuildGraph(heightmap).BellmanFord(Climbable(heightmap), vertices(heightmap, sources)...)
+	if err != nil {
+		return nil, false // unreachable, all weights are positive
+	}
+	return pathTo(heightmap, paths, end)
+}
+
+// Dijkstra expands the squares in the order of their distance from the
+// sources.
+func Dijkstra(heightmap *Heightmap, sources []geom.Point, end geom.Point) ([]geom.Point, bool) {
+	paths, err := BuildGraph(heightmap).Dijkstra(Climbable(heightmap), vertices(heightmap, sources)...)
+	if err != nil {
+		return nil, false // unreachable, all weights are positive
+	}
+	return pathTo(heightmap, paths, end)
+}
+
+// AStar expands the squares in the order of their distance from the sources
+// plus their Manhattan distance to end, which never overestimates the
+// remaining steps.
+func AStar(heightmap *Heightmap, sources []geom.Point, end geom.Point) ([]geom.Point, bool) {
+	estimate := func(v int) int {
+		return geom.Manhattan(Point(heightmap, v), end)
+	}
+	paths, err := BuildGraph(heightmap).AStar(Climbable(heightmap), Vertex(heightmap, end), estimate, vertices(heightmap, sources)...)
+	if err != nil {
+		return nil, false // unreachable, all weights are positive
+	}
+	return pathTo(heightmap, paths, end)
+}
+
+func pathTo(heightmap *Heightmap, paths *graph.Paths, end geom.Point) ([]geom.Point, bool) {
+	path := paths.PathTo(Vertex(heightmap, end))
+	if path == nil {
+		return nil, false
+	}
+	return points(heightmap, path), true
+}
+
+func reverse(path []geom.Point) {
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+}
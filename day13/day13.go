@@ -0,0 +1,264 @@
+// Package day13 solves "Distress Signal".
+package day13
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc/input"
+	"aoc/solver"
+)
+
+func init() {
+	solver.Register(13, Solver{})
+}
+
+type Solver struct{}
+
+// ---------------------------------- Packet ----------------------------------
+
+// Packet is either an integer or a list of packets.
+type Packet struct {
+	IsList bool
+	Value  int
+	List   []Packet
+}
+
+// Int returns the integer packet n.
+func Int(n int) Packet {
+	return Packet{Value: n}
+}
+
+// List returns the list packet holding ps.
+func List(ps ...Packet) Packet {
+	if ps == nil {
+		ps = []Packet{}
+	}
+	return Packet{IsList: true, List: ps}
+}
+
+// AsList returns p itself if it is a list, else a list holding p.
+func (p Packet) AsList() Packet {
+	if p.IsList {
+		return p
+	}
+	return List(p)
+}
+
+// String formats p the way it is written in the input, e.g. "[1,[2,3]]".
+func (p Packet) String() string {
+	var sb strings.Builder
+	p.write(&sb)
+	return sb.String()
+}
+
+func (p Packet) write(sb *strings.Builder) {
+	if !p.IsList {
+		sb.WriteString(strconv.Itoa(p.Value))
+		return
+	}
+	sb.WriteByte('[')
+	for i, q := range p.List {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		q.write(sb)
+	}
+	sb.WriteByte(']')
+}
+
+// Compare returns -1 if a comes before b, +1 if b comes before a and 0 if
+// their order can't be decided. Integers are compared by value, lists
+// element by element with the shorter list running out first coming first.
+// An integer compared with a list is treated as list holding the integer.
+func Compare(a, b Packet) int {
+	if !a.IsList && !b.IsList {
+		switch {
+		case a.Value < b.Value:
+			return -1
+		case a.Value > b.Value:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	a, b = a.AsList(), b.AsList()
+	for i := 0; i < len(a.List) && i < len(b.List); i++ {
+		if c := Compare(a.List[i], b.List[i]); c != 0 {
+			return c
+		}
+	}
+	return Compare(Int(len(a.List)), Int(len(b.List)))
+}
+
+// Packets sorts packets into the right order.
+type Packets []Packet
+
+func (ps Packets) Len() int           { return len(ps) }
+func (ps Packets) Less(i, j int) bool { return Compare(ps[i], ps[j]) < 0 }
+func (ps Packets) Swap(i, j int)      { ps[i], ps[j] = ps[j], ps[i] }
+
+// Index returns the 1-based position of the first packet equal to p or 0
+// if there is none.
+func (ps Packets) Index(p Packet) int {
+	for i, q := range ps {
+		if q.String() == p.String() {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// --------------------------------- Parsing ----------------------------------
+
+// Parse reads a packet like "[1,[2,[]],3]". Every packet is a list.
+func Parse(f input.Field) (Packet, error) {
+	p := &parser{f: f}
+	if p.peek() != '[' {
+		return Packet{}, p.errorf(1, "packet must be a list")
+	}
+
+	packet, err := p.packet()
+	if err != nil {
+		return Packet{}, err
+	}
+	if p.pos < len(f.Text) {
+		return Packet{}, p.errorf(len(f.Text)-p.pos, "unexpected text after packet")
+	}
+	return packet, nil
+}
+
+// MustParse is like Parse but panics on malformed packets. It simplifies
+// writing fixed packets like the divider packets.
+func MustParse(s string) Packet {
+	p, err := Parse(input.Line(s))
+	if err != nil {
+		panic(fmt.Sprintf("day13: MustParse(%q): %v", s, err))
+	}
+	return p
+}
+
+type parser struct {
+	f   input.Field
+	pos int
+}
+
+func (p *parser) peek() byte {
+	if p.pos >= len(p.f.Text) {
+		return 0
+	}
+	return p.f.Text[p.pos]
+}
+
+func (p *parser) errorf(length int, format string, args ...any) *input.ParseError {
+	text := ""
+	if p.pos < len(p.f.Text) {
+		text = p.f.Text[p.pos : p.pos+length]
+	}
+	return &input.ParseError{
+		Column: p.f.Column + p.pos,
+		Text:   text,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) packet() (Packet, error) {
+	switch c := p.peek(); {
+	case c == '[':
+		return p.list()
+	case '0' <= c && c <= '9':
+		return p.integer()
+	case c == 0:
+		return Packet{}, p.errorf(0, "unexpected end of packet")
+	default:
+		return Packet{}, p.errorf(1, "expected integer or list")
+	}
+}
+
+func (p *parser) list() (Packet, error) {
+	p.pos++ // '['
+	list := List()
+	if p.peek() == ']' {
+		p.pos++
+		return list, nil
+	}
+
+	for {
+		q, err := p.packet()
+		if err != nil {
+			return Packet{}, err
+		}
+		list.List = append(list.List, q)
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return list, nil
+		case 0:
+			return Packet{}, p.errorf(0, "missing ']'")
+		default:
+			return Packet{}, p.errorf(1, "expected ',' or ']'")
+		}
+	}
+}
+
+func (p *parser) integer() (Packet, error) {
+	start := p.pos
+	for c := p.peek(); '0' <= c && c <= '9'; c = p.peek() {
+		p.pos++
+	}
+	n, err := input.Field{Text: p.f.Text[start:p.pos], Column: p.f.Column + start}.Int()
+	if err != nil {
+		return Packet{}, err
+	}
+	return Int(n), nil
+}
+
+// ReadPackets reads all packets of r, ignoring the blank lines between the
+// pairs.
+func ReadPackets(r io.Reader) (Packets, error) {
+	var packets Packets
+	err := input.EachLine(r, func(line string) error {
+		if line == "" {
+			return nil
+		}
+		p, err := Parse(input.Line(line))
+		if err != nil {
+			return err
+		}
+		packets = append(packets, p)
+		return nil
+	})
+	return packets, err
+}
+
+// ReadPairs reads the blank-line-separated pairs of packets of r.
+func ReadPairs(r io.Reader) ([][2]Packet, error) {
+	var pairs [][2]Packet
+	err := input.EachBlock(r, func(block []string) error {
+		if len(block) != 2 {
+			return input.Errorf("expected a pair of packets, got %d", len(block))
+		}
+		var pair [2]Packet
+		for i, line := range block {
+			p, err := Parse(input.Line(line))
+			if err != nil {
+				var pe *input.ParseError
+				if errors.As(err, &pe) {
+					pe.Line = i + 1
+				}
+				return err
+			}
+			pair[i] = p
+		}
+		pairs = append(pairs, pair)
+		return nil
+	})
+	return pairs, err
+}
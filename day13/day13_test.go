@@ -0,0 +1,124 @@
+package day13
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"aoc/input"
+	"aoc/solver/solvertest"
+)
+
+const example = `[1,1,3,1,1]
+[1,1,5,1,1]
+
+[[1],[2,3,4]]
+[[1],4]
+
+[9]
+[[8,7,6]]
+
+[[4,4],4,4]
+[[4,4],4,4,4]
+
+[7,7,7,7]
+[7,7,7]
+
+[]
+[3]
+
+[[[]]]
+[[]]
+
+[1,[2,[3,[4,[5,6,7]]]],8,9]
+[1,[2,[3,[4,[5,6,0]]]],8,9]
+`
+
+func TestExamples(t *testing.T) {
+	solvertest.Run(t, Solver{}, []solvertest.Example{
+		{Name: "example", Input: example, Part1: "13", Part2: "140"},
+	})
+}
+
+func TestRoundTrip(t *testing.T) {
+	packets, err := ReadPackets(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Fields(example)
+	for i, p := range packets {
+		if got := p.String(); got != lines[i] {
+			t.Errorf("String() = %q, want %q", got, lines[i])
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"[1,1,3,1,1]", "[1,1,5,1,1]", -1},
+		{"[[1],[2,3,4]]", "[[1],4]", -1},
+		{"[9]", "[[8,7,6]]", 1},
+		{"[7,7,7,7]", "[7,7,7]", 1},
+		{"[[[]]]", "[[]]", 1},
+		{"[[2]]", "[2]", 0},
+		{"[10]", "[10]", 0},
+	}
+
+	for _, tt := range tests {
+		a, b := MustParse(tt.a), MustParse(tt.b)
+		if got := Compare(a, b); got != tt.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(b, a); got != -tt.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	packets, err := ReadPackets(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+	packets = append(packets, Dividers[0], Dividers[1])
+	sort.Sort(packets)
+
+	want := []string{
+		"[]", "[[]]", "[[[]]]", "[1,1,3,1,1]", "[1,1,5,1,1]", "[[1],[2,3,4]]",
+		"[1,[2,[3,[4,[5,6,0]]]],8,9]", "[1,[2,[3,[4,[5,6,7]]]],8,9]", "[[1],4]",
+		"[[2]]", "[3]", "[[4,4],4,4]", "[[4,4],4,4,4]", "[[6]]", "[7,7,7]",
+		"[7,7,7,7]", "[[8,7,6]]", "[9]",
+	}
+	for i, p := range packets {
+		if p.String() != want[i] {
+			t.Errorf("packet %d = %s, want %s", i+1, p, want[i])
+		}
+	}
+}
+
+func TestMalformedInput(t *testing.T) {
+	tests := []struct {
+		input        string
+		line, column int
+	}{
+		{"[1,2]\n[1,x]\n", 2, 4},
+		{"[1,2]\n[1,2\n", 2, 5},
+		{"[1,2]]\n[1,2]\n", 1, 6},
+		{"1\n[1,2]\n", 1, 1},
+		{"[1,,2]\n[1]\n", 1, 4},
+		{"[1]\n[2]\n\n[3]\n", 4, 0},
+	}
+
+	for _, tt := range tests {
+		_, err := Solver{}.Part1(strings.NewReader(tt.input))
+		var pe *input.ParseError
+		if !errors.As(err, &pe) || pe.Line != tt.line || pe.Column != tt.column {
+			t.Errorf("Part1(%q) error = %v, want parse error at %d:%d", tt.input, err, tt.line, tt.column)
+		}
+	}
+}
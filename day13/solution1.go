@@ -0,0 +1,23 @@
+package day13
+
+import (
+	"io"
+
+	"aoc/solver"
+)
+
+func (Solver) Part1(r io.Reader) (solver.Answer, error) {
+	pairs, err := ReadPairs(r)
+	if err != nil {
+		return "", err
+	}
+
+	sum := 0
+	for i, pair := range pairs {
+		if Compare(pair[0], pair[1]) < 0 {
+			sum += i + 1
+		}
+	}
+
+	return solver.Int(sum), nil
+}
@@ -0,0 +1,23 @@
+package day13
+
+import (
+	"io"
+	"sort"
+
+	"aoc/solver"
+)
+
+// Dividers are the two additional packets of part 2.
+var Dividers = [2]Packet{MustParse("[[2]]"), MustParse("[[6]]")}
+
+func (Solver) Part2(r io.Reader) (solver.Answer, error) {
+	packets, err := ReadPackets(r)
+	if err != nil {
+		return "", err
+	}
+
+	packets = append(packets, Dividers[0], Dividers[1])
+	sort.Sort(packets)
+
+	return solver.Int(packets.Index(Dividers[0]) * packets.Index(Dividers[1])), nil
+}
@@ -0,0 +1,171 @@
+// Package day14 solves "Regolith Reservoir".
+package day14
+
+import (
+	"errors"
+	"io"
+
+	"aoc/geom"
+	"aoc/grid"
+	"aoc/input"
+	"aoc/solver"
+)
+
+func init() {
+	solver.Register(14, Solver{})
+}
+
+type Solver struct{}
+
+// Point is the position of a cell, Y grows downwards.
+type Point = geom.Point
+
+// ------------------------------- ObstacleMap --------------------------------
+
+// ObstacleMap marks the cells blocked by rock or resting sand.
+type ObstacleMap struct {
+	*grid.Grid[bool]
+}
+
+func NewObstacleMap(width, height int) *ObstacleMap {
+	return &ObstacleMap{grid.New[bool](width, height)}
+}
+
+func (obm *ObstacleMap) AddObstacle(p Point) {
+	obm.Set(p, true)
+}
+
+// AddLine blocks all cells of the horizontal or vertical line, both ends
+// included.
+func (obm *ObstacleMap) AddLine(line geom.Segment) {
+	if !line.AxisAligned() {
+		panic("not a horizontal or vertical line")
+	}
+	for _, p := range line.Points() {
+		obm.AddObstacle(p)
+	}
+}
+
+func (obm *ObstacleMap) Collision(p Point) (bool, error) {
+	if obm.OutOfMap(p) {
+		return false, errors.New("Out of map")
+	}
+	return obm.At(p), nil
+}
+
+func (obm *ObstacleMap) OutOfMap(p Point) bool {
+	return !obm.In(p)
+}
+
+// --------------------------------- RockPath ---------------------------------
+
+type RockPath struct {
+	rocks  []Point
+	Bounds geom.Rect
+}
+
+func NewRockPath() *RockPath {
+	return &RockPath{rocks: make([]Point, 0)}
+}
+
+func (rp *RockPath) AddPoint(p Point) {
+	rp.rocks = append(rp.rocks, p)
+	rp.Bounds = rp.Bounds.Extend(p)
+}
+
+// ----------------------------------- Sand -----------------------------------
+
+// Source is where the sand comes from.
+var Source = Point{X: 500, Y: 0}
+
+type Sand struct {
+	Pos               Point
+	falling, outOfMap bool
+}
+
+func NewSand(p Point) *Sand {
+	return &Sand{p, true, false}
+}
+
+func (s *Sand) PossibleMoves() [3]Point {
+	return [3]Point{s.Pos.Add(geom.Down.Step()), s.Pos.Add(geom.DownLeft.Step()), s.Pos.Add(geom.DownRight.Step())}
+}
+
+func (s *Sand) Falling() bool {
+	return s.falling
+}
+
+func (s *Sand) OutOfMap() bool {
+	return s.outOfMap
+}
+
+func (s *Sand) Update(obstacles *ObstacleMap) {
+	for _, move := range s.PossibleMoves() {
+		if result, err := obstacles.Collision(move); !result {
+			if err != nil {
+				s.outOfMap = true
+			}
+			s.Pos = move
+			return
+		}
+	}
+	s.falling = false
+}
+
+// ----------------------------------- Misc -----------------------------------
+
+// GetWidthAndHeight returns the size of the smallest map starting at the
+// origin which holds all rock paths.
+func GetWidthAndHeight(rockPaths []*RockPath) (int, int) {
+	var bounds geom.Rect
+	for _, rockPath := range rockPaths {
+		bounds = bounds.Union(rockPath.Bounds)
+	}
+	return bounds.Max.X, bounds.Max.Y
+}
+
+func PopulateObstacleMap(obm *ObstacleMap, rp *RockPath) {
+	rpLen := len(rp.rocks)
+	if rpLen == 1 {
+		obm.AddObstacle(rp.rocks[0])
+		return
+	}
+	for i := 0; i < rpLen-1; i++ {
+		obm.AddLine(geom.Seg(rp.rocks[i], rp.rocks[i+1]))
+	}
+}
+
+// ReadRockPaths reads one path of horizontal and vertical rock lines per
+// line, e.g. "498,4 -> 498,6 -> 496,6".
+func ReadRockPaths(r io.Reader) ([]*RockPath, error) {
+	var rockPaths []*RockPath
+	err := input.EachLine(r, func(line string) error {
+		rockPath := NewRockPath()
+		for _, f := range input.Split(line, "->") {
+			f = f.TrimSpace()
+			coords, err := input.IntList(f, ",")
+			if err != nil {
+				return err
+			}
+			if len(coords) != 2 {
+				return f.Errorf("expected \"<x>,<y>\"")
+			}
+			point := Point{X: coords[0], Y: coords[1]}
+			if point.X < 0 || point.Y < 0 {
+				return f.Errorf("negative coordinate")
+			}
+			if n := len(rockPath.rocks); n > 0 && !geom.Seg(rockPath.rocks[n-1], point).AxisAligned() {
+				return f.Errorf("not a horizontal or vertical line")
+			}
+			rockPath.AddPoint(point)
+		}
+		rockPaths = append(rockPaths, rockPath)
+		return nil
+	})
+
+	if err == nil && len(rockPaths) == 0 {
+		return nil, errors.New("no rock paths")
+	}
+
+	return rockPaths, err
+}
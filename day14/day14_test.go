@@ -0,0 +1,17 @@
+package day14
+
+import (
+	"testing"
+
+	"aoc/solver/solvertest"
+)
+
+const example = `498,4 -> 498,6 -> 496,6
+503,4 -> 502,4 -> 502,9 -> 494,9
+`
+
+func TestExamples(t *testing.T) {
+	solvertest.Run(t, Solver{}, []solvertest.Example{
+		{Name: "example", Input: example, Part1: "24", Part2: "93"},
+	})
+}
@@ -0,0 +1,121 @@
+// Package day15 solves "Beacon Exclusion Zone".
+package day15
+
+import (
+	"flag"
+	"io"
+	"regexp"
+
+	"aoc/geom"
+	"aoc/input"
+	"aoc/interval"
+	"aoc/solver"
+)
+
+func init() {
+	solver.Register(15, &Solver{Row: 2000000, SearchRange: 4000000, Search: DefaultSearch})
+}
+
+// Solver is parametrised by the row inspected in part 1, the upper bound of
+// both coordinates of the distress beacon searched in part 2 and the name
+// of the search used to find it, see Searches. An empty Search selects
+// DefaultSearch.
+type Solver struct {
+	Row, SearchRange int
+	Search           string
+}
+
+func (s *Solver) Flags(fs *flag.FlagSet) {
+	fs.IntVar(&s.Row, "row", s.Row, "row to inspect in part 1")
+	fs.IntVar(&s.SearchRange, "range", s.SearchRange, "search range of the distress beacon in part 2")
+	fs.StringVar(&s.Search, "search", s.Search, "search for the distress beacon in part 2: rows or rotated")
+}
+
+// ---------------------------------- Sensor ----------------------------------
+
+type Sensor struct {
+	Pos           geom.Point
+	ClosestBeacon *Beacon
+}
+
+func NewSensor(p geom.Point, b *Beacon) *Sensor {
+	return &Sensor{p, b}
+}
+
+func (s *Sensor) DistanceToClosetstBeacon() int {
+	return geom.Manhattan(s.Pos, s.ClosestBeacon.Pos)
+}
+
+// ---------------------------------- Beacon ----------------------------------
+
+type Beacon struct {
+	Pos geom.Point
+}
+
+func NewBeacon(p geom.Point) *Beacon {
+	return &Beacon{p}
+}
+
+// ----------------------------------- Misc -----------------------------------
+
+var sensorPattern = regexp.MustCompile(`^Sensor at x=(-?\d+), y=(-?\d+): closest beacon is at x=(-?\d+), y=(-?\d+)$`)
+
+// GetSensorFromRawLine parses a line like "Sensor at x=2, y=18: closest
+// beacon is at x=-2, y=15".
+func GetSensorFromRawLine(line string) (*Sensor, error) {
+	groups, err := input.Submatches(sensorPattern, line)
+	if err != nil {
+		return nil, err
+	}
+
+	var coords [4]int
+	for i, group := range groups {
+		if coords[i], err = group.Int(); err != nil {
+			return nil, err
+		}
+	}
+
+	beacon := NewBeacon(geom.Pt(coords[2], coords[3]))
+	sensor := NewSensor(geom.Pt(coords[0], coords[1]), beacon)
+
+	return sensor, nil
+}
+
+// Coverage returns the area in which the sensor rules out other beacons.
+func (s *Sensor) Coverage() geom.Diamond {
+	return geom.Diamond{Center: s.Pos, Radius: s.DistanceToClosetstBeacon()}
+}
+
+// Covered reports whether p is in the coverage area of one of the sensors.
+func Covered(sensors []*Sensor, p geom.Point) bool {
+	for _, sensor := range sensors {
+		if sensor.Coverage().Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// CoverRow adds the x coordinates of row y which are covered by the
+// sensors to covered.
+func CoverRow(covered *interval.Set, sensors []*Sensor, y int) {
+	for _, sensor := range sensors {
+		if row, ok := sensor.Coverage().Row(y); ok {
+			covered.Add(interval.New(row.A.X, row.B.X))
+		}
+	}
+}
+
+func ReadSensors(r io.Reader) ([]*Sensor, error) {
+	var sensors []*Sensor
+	err := input.EachLine(r, func(line string) error {
+		sensor, err := GetSensorFromRawLine(line)
+		if err != nil {
+			return err
+		}
+		sensors = append(sensors, sensor)
+		return nil
+	})
+
+	return sensors, err
+}
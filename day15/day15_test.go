@@ -0,0 +1,104 @@
+package day15
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"aoc/geom"
+	"aoc/solver/solvertest"
+)
+
+const example = `Sensor at x=2, y=18: closest beacon is at x=-2, y=15
+Sensor at x=9, y=16: closest beacon is at x=10, y=16
+Sensor at x=13, y=2: closest beacon is at x=15, y=3
+Sensor at x=12, y=14: closest beacon is at x=10, y=16
+Sensor at x=10, y=20: closest beacon is at x=10, y=16
+Sensor at x=14, y=17: closest beacon is at x=10, y=16
+Sensor at x=8, y=7: closest beacon is at x=2, y=10
+Sensor at x=2, y=0: closest beacon is at x=2, y=10
+Sensor at x=0, y=11: closest beacon is at x=2, y=10
+Sensor at x=20, y=14: closest beacon is at x=25, y=17
+Sensor at x=17, y=20: closest beacon is at x=21, y=22
+Sensor at x=16, y=7: closest beacon is at x=15, y=3
+Sensor at x=14, y=3: closest beacon is at x=15, y=3
+Sensor at x=20, y=1: closest beacon is at x=15, y=3
+`
+
+func TestExamples(t *testing.T) {
+	for name := range Searches {
+		solvertest.Run(t, &Solver{Row: 10, SearchRange: 20, Search: name}, []solvertest.Example{
+			{Name: "example/" + name, Input: example, Part1: "26", Part2: "56000011"},
+		})
+	}
+}
+
+func TestSearchesAgree(t *testing.T) {
+	sensors, err := ReadSensors(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, search := range Searches {
+		if p, ok := search(sensors, 20); !ok || p != geom.Pt(14, 11) {
+			t.Errorf("%s search = %v, %v, want (14,11)", name, p, ok)
+		}
+	}
+
+	// random puzzles with exactly one uncovered position
+	const searchRange = 20
+	rnd := rand.New(rand.NewSource(1))
+	found := 0
+	for tries := 0; found < 100 && tries < 100000; tries++ {
+		sensors = nil
+		for n := 3 + rnd.Intn(8); n > 0; n-- {
+			pos := geom.Pt(rnd.Intn(31)-5, rnd.Intn(31)-5)
+			beacon := pos.Add(geom.Pt(rnd.Intn(21)-10, rnd.Intn(21)-10))
+			sensors = append(sensors, NewSensor(pos, NewBeacon(beacon)))
+		}
+
+		var uncovered []geom.Point
+		for y := 0; y <= searchRange; y++ {
+			for x := 0; x <= searchRange; x++ {
+				if p := geom.Pt(x, y); !Covered(sensors, p) {
+					uncovered = append(uncovered, p)
+				}
+			}
+		}
+		if len(uncovered) != 1 {
+			continue
+		}
+		found++
+
+		for name, search := range Searches {
+			if p, ok := search(sensors, searchRange); !ok || p != uncovered[0] {
+				t.Fatalf("%s search = %v, %v, want %v", name, p, ok, uncovered[0])
+			}
+		}
+	}
+
+	if found < 100 {
+		t.Errorf("only %d random puzzles with a single uncovered position", found)
+	}
+
+	// degenerate search areas and puzzles
+	far := []*Sensor{NewSensor(geom.Pt(10, 10), NewBeacon(geom.Pt(10, 12)))}
+	for _, tt := range []struct {
+		name        string
+		sensors     []*Sensor
+		searchRange int
+		want        geom.Point
+		ok          bool
+	}{
+		{"single covered position", sensors, 0, geom.Point{}, false},
+		{"single uncovered position", far, 0, geom.Pt(0, 0), true},
+		{"no sensors", nil, 20, geom.Pt(0, 0), true},
+		{"no sensors, single position", nil, 0, geom.Pt(0, 0), true},
+		{"empty search area", far, -1, geom.Point{}, false},
+	} {
+		for name, search := range Searches {
+			if p, ok := search(tt.sensors, tt.searchRange); p != tt.want || ok != tt.ok {
+				t.Errorf("%s: %s search = %v, %v, want %v, %v", tt.name, name, p, ok, tt.want, tt.ok)
+			}
+		}
+	}
+}
@@ -1,274 +1,132 @@
-package main
+package day15
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"os"
-	"regexp"
-	"sort"
-	"strconv"
-)
-
-// ---------------------------------- Sensor ----------------------------------
-
-type Sensor struct {
-	Pos           Point
-	ClosestBeacon *Beacon
-}
-
-func NewSensor(p Point, b *Beacon) *Sensor {
-	return &Sensor{p, b}
-}
-
-func (s *Sensor) DistanceToClosetstBeacon() int {
-	return ManhattanDistance(s.Pos, s.ClosestBeacon.Pos)
-}
-
-// ---------------------------------- Beacon ----------------------------------
-
-type Beacon struct {
-	Pos Point
-}
-
-func NewBeacon(p Point) *Beacon {
-	return &Beacon{p}
-}
-
-// ----------------------------------- Misc -----------------------------------
-
-func GetSensorFromRawLine(line string) *Sensor {
-	var (
-		re         *regexp.Regexp
-		matches    []string
-		rawX, rawY string
-		x, y       int
-	)
-
-	pattern := `Sensor at x=(-*\d+), y=(-*\d+): closest beacon is at x=(-*\d+), y=(-*\d+)`
-	re = regexp.MustCompile(pattern)
-	matches = re.FindStringSubmatch(line)
-
-	rawX, rawY = matches[3], matches[4]
-	x, _ = strconv.Atoi(rawX)
-	y, _ = strconv.Atoi(rawY)
-	beacon := NewBeacon(Point{x, y})
-
-	rawX, rawY = matches[1], matches[2]
-	x, _ = strconv.Atoi(rawX)
-	y, _ = strconv.Atoi(rawY)
-	sensor := NewSensor(Point{x, y}, beacon)
+	"io"
 
-	return sensor
-}
-
-func EachLineDo(f func(string)) error {
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		f(line)
-	}
-
-	err := scanner.Err()
-
-	return err
-}
-
-type Point struct {
-	X, Y int
-}
+	"aoc/geom"
+	"aoc/interval"
+	"aoc/solver"
+)
 
-func (p *Point) Equal(o Point) bool {
-	return p.X == o.X && p.Y == o.Y
-}
+// DefaultSearch is the search used unless another one is selected.
+const DefaultSearch = "rotated"
 
-func (p *Point) Diff(o Point) Point {
-	return Point{p.X - o.X, p.Y - o.Y}
+// Searches maps the names accepted by the -search flag to the functions
+// finding the only position in [0, searchRange]² not covered by a sensor.
+var Searches = map[string]func(sensors []*Sensor, searchRange int) (geom.Point, bool){
+	"rows":    RowScan,
+	"rotated": RotatedSearch,
 }
 
-func (p *Point) Chop(r1, r2 int) {
-	switch {
-	case p.X < r1:
-		p.X = r1
-	case p.X > r2:
-		p.X = r2
+func (s *Solver) Part2(r io.Reader) (solver.Answer, error) {
+	name := s.Search
+	if name == "" {
+		name = DefaultSearch
 	}
-	switch {
-	case p.Y < r1:
-		p.Y = r1
-	case p.Y > r2:
-		p.Y = r2
+	search, ok := Searches[name]
+	if !ok {
+		return "", fmt.Errorf("unknown search %q", name)
 	}
-}
-
-type Line struct {
-	P1, P2 Point
-}
-
-func (l *Line) Includes(o Line) bool {
-	if !(l.Horizontal() && o.Horizontal() && l.P1.Y == o.P1.Y) {
-		panic("both lines need to be horizontal and on the same y-coordinate")
-	}
-	return l.IncludesPoint(o.P1) && l.IncludesPoint(o.P2)
-}
-
-func (l *Line) IncludesPoint(p Point) bool {
-	return l.P1.Y == p.Y && l.P2.Y == p.Y && l.P1.X <= p.X && p.X <= l.P2.X
-}
-
-func (l *Line) Horizontal() bool {
-	return l.P1.Y == l.P2.Y
-}
-
-func (l *Line) Length() int {
-	return l.P2.X - l.P1.X + 1
-}
-
-type Circle struct {
-	Pos    Point
-	Radius int
-}
-
-func (c *Circle) IntersectsWithHorizontalLine(y int) bool {
-	return c.Pos.Y-c.Radius <= y && y <= c.Pos.Y+c.Radius
-}
 
-func (c *Circle) IntersectionsWithHorizontalLine(y int) (*Point, *Point) {
-	if !c.IntersectsWithHorizontalLine(y) {
-		return nil, nil
+	sensors, err := ReadSensors(r)
+	if err != nil {
+		return "", err
 	}
 
-	distance := Abs(y - c.Pos.Y)
-	if distance == c.Radius {
-		return &Point{c.Pos.X, y}, nil
+	p, ok := search(sensors, s.SearchRange)
+	if !ok {
+		return "", errors.New("no distress beacon found")
 	}
 
-	dx := c.Radius - distance
-	p1 := &Point{c.Pos.X - dx, y}
-	p2 := &Point{c.Pos.X + dx, y}
-
-	return p1, p2
+	return solver.Int(p.X*4000000 + p.Y), nil
 }
 
-func Abs(x int) int {
-	if x < 0 {
-		return -x
-	} else {
-		return x
-	}
-}
+// RowScan looks for the first gap in the coverage of each row.
+func RowScan(sensors []*Sensor, searchRange int) (geom.Point, bool) {
+	xs := interval.New(0, searchRange)
 
-func ManhattanDistance(p1, p2 Point) int {
-	diff := p1.Diff(p2)
-	return Abs(diff.X) + Abs(diff.Y)
-}
+	var covered interval.Set
+	for y := 0; y <= searchRange; y++ {
+		covered.Clear()
+		CoverRow(&covered, sensors, y)
 
-func GetAllIncluded(lines []Line) [][]Line {
-	var included [][]Line
-	alreadyIncluded := make(map[Line]bool)
-	idx := 0
-	for i, line := range lines {
-		if _, prs := alreadyIncluded[line]; prs {
-			continue
-		}
-		included = append(included, make([]Line, 0))
-		included[idx] = append(included[idx], line)
-		for _, other := range lines[i+1:] {
-			if line.Includes(other) {
-				alreadyIncluded[other] = true
-				included[idx] = append(included[idx], other)
-			}
+		if gaps := covered.Gaps(xs); len(gaps) > 0 {
+			return geom.Pt(gaps[0].Lo, y), true
 		}
-		idx++
 	}
-	return included
-}
 
-func usage() {
-	fmt.Fprintln(os.Stderr, "usage: go run solution1.go <NUMB>")
-	os.Exit(1)
-}
-
-// ----------------------------------- Main -----------------------------------
-
-func main() {
-	searchRange, _ := strconv.Atoi(os.Args[1])
-
-	var sensors []*Sensor
-	err := EachLineDo(func(line string) {
-		sensor := GetSensorFromRawLine(line)
-		sensors = append(sensors, sensor)
-	})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "reading stdin:", err)
-		os.Exit(1)
+	return geom.Point{}, false
+}
+
+// RotatedSearch walks the perimeter just outside of every coverage area
+// instead of scanning all rows.
+//
+// The uncovered position p has a covered neighbour unless the search area is
+// a single position or there are no sensors. Both are handled up front, the
+// latter answered like RowScan with the first position. Otherwise, as that
+// neighbour is covered by a sensor with radius r which doesn't cover p, p is
+// r+1 away from the sensor. So p lies on one of the four diagonal lines
+// bounding the perimeter of a sensor: in rotated coordinates u = x+y,
+// v = x-y the coverage areas are squares and these are the lines
+// u = u₀±(r+1) and v = v₀±(r+1) of a sensor at (u₀, v₀). Along each line the
+// sensors cover intervals of x, so only the gaps between them are left to
+// look at.
+func RotatedSearch(sensors []*Sensor, searchRange int) (geom.Point, bool) {
+	if searchRange < 0 {
+		return geom.Point{}, false
+	}
+	if searchRange == 0 || len(sensors) == 0 {
+		origin := geom.Pt(0, 0)
+		return origin, !Covered(sensors, origin)
 	}
 
-	for y := 0; y <= searchRange; y++ {
-		var ranges []Line
-		for _, sensor := range sensors {
-			pos := sensor.Pos
-			coverageArea := Circle{pos, sensor.DistanceToClosetstBeacon()}
-			intersect1, intersect2 := coverageArea.IntersectionsWithHorizontalLine(y)
-
-			beaconPos := sensor.ClosestBeacon.Pos
-			if beaconPos.Y == y {
-				ranges = append(ranges, Line{sensor.ClosestBeacon.Pos, sensor.ClosestBeacon.Pos})
+	var covered interval.Set
+
+	for _, outer := range sensors {
+		c, r := outer.Pos, outer.DistanceToClosetstBeacon()+1
+		u0, v0 := c.X+c.Y, c.X-c.Y
+
+		for _, u := range []int{u0 - r, u0 + r} {
+			// points (x, u-x) of the perimeter inside of the search area
+			xs := interval.New(max(0, u-searchRange, floorDiv(u+v0-r+1, 2)), min(searchRange, u, floorDiv(u+v0+r, 2)))
+			covered.Clear()
+			for _, sensor := range sensors {
+				su, sv, sr := sensor.Pos.X+sensor.Pos.Y, sensor.Pos.X-sensor.Pos.Y, sensor.DistanceToClosetstBeacon()
+				if geom.Abs(u-su) <= sr {
+					covered.Add(interval.New(floorDiv(u+sv-sr+1, 2), floorDiv(u+sv+sr, 2)))
+				}
 			}
-
-			switch {
-			case intersect1 == nil && intersect2 == nil:
-				// no intersection
-			case intersect2 == nil:
-				// tangent
-				intersect1.Chop(0, searchRange)
-				ranges = append(ranges, Line{*intersect1, *intersect1})
-			default:
-				// full intersection
-				intersect1.Chop(0, searchRange)
-				intersect2.Chop(0, searchRange)
-				ranges = append(ranges, Line{*intersect1, *intersect2})
+			if gaps := covered.Gaps(xs); len(gaps) > 0 {
+				return geom.Pt(gaps[0].Lo, u-gaps[0].Lo), true
 			}
 		}
 
-		sort.Slice(ranges, func(i, j int) bool {
-			return ranges[i].P1.X < ranges[j].P1.X
-		})
-
-		included := GetAllIncluded(ranges)
-		ranges = make([]Line, 0)
-		for _, include := range included {
-			ranges = append(ranges, include[0])
-		}
-
-		coverage := 0
-		rangeLen := len(ranges)
-		last := ranges[rangeLen-1]
-		for i, line := range ranges[:rangeLen-1] {
-			switch next := ranges[i+1]; {
-			case next.P1.X < line.P2.X:
-				coverage += line.Length() - line.P2.Diff(next.P1).X
-			default:
-				coverage += line.Length()
+		for _, v := range []int{v0 - r, v0 + r} {
+			// points (x, x-v) of the perimeter inside of the search area
+			xs := interval.New(max(0, v, floorDiv(v+u0-r+1, 2)), min(searchRange, v+searchRange, floorDiv(v+u0+r, 2)))
+			covered.Clear()
+			for _, sensor := range sensors {
+				su, sv, sr := sensor.Pos.X+sensor.Pos.Y, sensor.Pos.X-sensor.Pos.Y, sensor.DistanceToClosetstBeacon()
+				if geom.Abs(v-sv) <= sr {
+					covered.Add(interval.New(floorDiv(v+su-sr+1, 2), floorDiv(v+su+sr, 2)))
+				}
+			}
+			if gaps := covered.Gaps(xs); len(gaps) > 0 {
+				return geom.Pt(gaps[0].Lo, gaps[0].Lo-v), true
 			}
 		}
-		coverage += last.Length()
-		coverage -= rangeLen
+	}
 
-		if coverage != searchRange-2 {
-			continue
-		}
+	return geom.Point{}, false
+}
 
-		var p Point
-		for i := 1; i < len(ranges); i++ {
-			prev, cur := ranges[i-1], ranges[i]
-			if d := cur.P1.Diff(prev.P2); d.X < 2 {
-				continue
-			}
-			p = Point{prev.P2.X + 1, y}
-			break
-		}
-		fmt.Println("tuning frequenzy:", p.X*4000000+p.Y)
-		break
+// floorDiv divides rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
 	}
+	return q
 }
@@ -0,0 +1,20 @@
+// Package days imports every day, which registers its solver on import.
+package days
+
+import (
+	_ "aoc/day01"
+	_ "aoc/day02"
+	_ "aoc/day03"
+	_ "aoc/day04"
+	_ "aoc/day05"
+	_ "aoc/day06"
+	_ "aoc/day07"
+	_ "aoc/day08"
+	_ "aoc/day09"
+	_ "aoc/day10"
+	_ "aoc/day11"
+	_ "aoc/day12"
+	_ "aoc/day13"
+	_ "aoc/day14"
+	_ "aoc/day15"
+)
@@ -0,0 +1,180 @@
+// Package geom contains the two-dimensional integer geometry shared by the
+// puzzles: points, directions, distances and a few simple shapes.
+//
+// Y grows downwards like the rows of a character map, so Up is the
+// direction of decreasing Y.
+package geom
+
+import (
+	"fmt"
+)
+
+// ---------------------------------- Point -----------------------------------
+
+// Point is a position or a vector on the integer plane.
+type Point struct {
+	X, Y int
+}
+
+// Pt is shorthand for Point{x, y}.
+func Pt(x, y int) Point {
+	return Point{x, y}
+}
+
+func (p Point) Add(o Point) Point {
+	return Point{p.X + o.X, p.Y + o.Y}
+}
+
+func (p Point) Sub(o Point) Point {
+	return Point{p.X - o.X, p.Y - o.Y}
+}
+
+// Mul scales p by k.
+func (p Point) Mul(k int) Point {
+	return Point{p.X * k, p.Y * k}
+}
+
+func (p Point) Neg() Point {
+	return Point{-p.X, -p.Y}
+}
+
+// Abs returns p with both coordinates made non-negative.
+func (p Point) Abs() Point {
+	return Point{Abs(p.X), Abs(p.Y)}
+}
+
+// Sign returns the unit step from the origin towards p, e.g. (1,-1) for
+// (5,-3) and (0,1) for (0,7).
+func (p Point) Sign() Point {
+	return Point{Sign(p.X), Sign(p.Y)}
+}
+
+// Clamp returns the point of r nearest to p. r must not be empty.
+func (p Point) Clamp(r Rect) Point {
+	return Point{clamp(p.X, r.Min.X, r.Max.X-1), clamp(p.Y, r.Min.Y, r.Max.Y-1)}
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%d,%d)", p.X, p.Y)
+}
+
+func clamp(x, lo, hi int) int {
+	switch {
+	case x < lo:
+		return lo
+	case x > hi:
+		return hi
+	default:
+		return x
+	}
+}
+
+// Abs returns the absolute value of x.
+func Abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of x.
+func Sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// -------------------------------- Distances ---------------------------------
+
+// Manhattan returns the taxicab distance |dx| + |dy| of a and b.
+func Manhattan(a, b Point) int {
+	d := a.Sub(b).Abs()
+	return d.X + d.Y
+}
+
+// Chebyshev returns the king's move distance max(|dx|, |dy|) of a and b.
+func Chebyshev(a, b Point) int {
+	d := a.Sub(b).Abs()
+	return max(d.X, d.Y)
+}
+
+// ------------------------------- Directions ---------------------------------
+
+// Dir is one of the eight compass directions, ordered clockwise starting
+// with Up.
+type Dir int
+
+const (
+	Up Dir = iota
+	UpRight
+	Right
+	DownRight
+	Down
+	DownLeft
+	Left
+	UpLeft
+)
+
+// Dirs4 are the horizontal and vertical directions, Dirs8 all directions.
+var (
+	Dirs4 = []Dir{Up, Right, Down, Left}
+	Dirs8 = []Dir{Up, UpRight, Right, DownRight, Down, DownLeft, Left, UpLeft}
+)
+
+var steps = [...]Point{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+
+var dirNames = [...]string{"Up", "UpRight", "Right", "DownRight", "Down", "DownLeft", "Left", "UpLeft"}
+
+// Step returns the unit vector of d.
+func (d Dir) Step() Point {
+	return steps[d]
+}
+
+// TurnRight returns the direction 90 degrees clockwise of d.
+func (d Dir) TurnRight() Dir {
+	return (d + 2) % 8
+}
+
+// TurnLeft returns the direction 90 degrees counter-clockwise of d.
+func (d Dir) TurnLeft() Dir {
+	return (d + 6) % 8
+}
+
+func (d Dir) Opposite() Dir {
+	return (d + 4) % 8
+}
+
+// Diagonal reports whether d is one of the four diagonal directions.
+func (d Dir) Diagonal() bool {
+	return d%2 == 1
+}
+
+func (d Dir) String() string {
+	if d < 0 || int(d) >= len(dirNames) {
+		return fmt.Sprintf("Dir(%d)", int(d))
+	}
+	return dirNames[d]
+}
+
+// ParseDir parses the single letter names of the horizontal and vertical
+// directions used by the puzzles: U, D, L, R, the compass points N, E, S,
+// W and the arrows ^, >, v, <.
+func ParseDir(s string) (Dir, error) {
+	switch s {
+	case "U", "N", "^":
+		return Up, nil
+	case "R", "E", ">":
+		return Right, nil
+	case "D", "S", "v":
+		return Down, nil
+	case "L", "W", "<":
+		return Left, nil
+	default:
+		return 0, fmt.Errorf("invalid direction %q", s)
+	}
+}
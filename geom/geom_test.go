@@ -0,0 +1,161 @@
+package geom
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+// small keeps the generated coordinates far away from overflows.
+type small int16
+
+func pt(x, y small) Point {
+	return Point{int(x), int(y)}
+}
+
+func check(t *testing.T, f any) {
+	t.Helper()
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPointArithmetic(t *testing.T) {
+	check(t, func(ax, ay, bx, by small) bool {
+		a, b := pt(ax, ay), pt(bx, by)
+		return a.Add(b).Sub(b) == a && a.Add(a.Neg()) == Point{} && a.Mul(2) == a.Add(a)
+	})
+	check(t, func(x, y small) bool {
+		p, s := pt(x, y), pt(x, y).Sign()
+		return Chebyshev(Point{}, s) <= 1 && s.X*p.X == Abs(p.X) && s.Y*p.Y == Abs(p.Y)
+	})
+}
+
+func TestDistances(t *testing.T) {
+	check(t, func(ax, ay, bx, by, cx, cy small) bool {
+		a, b, c := pt(ax, ay), pt(bx, by), pt(cx, cy)
+		m, ch := Manhattan(a, b), Chebyshev(a, b)
+		return m == Manhattan(b, a) && ch == Chebyshev(b, a) &&
+			ch <= m && m <= 2*ch &&
+			Manhattan(a, c) <= m+Manhattan(b, c) &&
+			Chebyshev(a, c) <= ch+Chebyshev(b, c) &&
+			(m == 0) == (a == b)
+	})
+}
+
+func TestDirections(t *testing.T) {
+	for _, d := range Dirs8 {
+		if d.TurnRight().TurnLeft() != d || d.TurnRight().TurnRight() != d.Opposite() {
+			t.Errorf("turning %v is inconsistent", d)
+		}
+		if d.Opposite().Step() != d.Step().Neg() {
+			t.Errorf("%v.Opposite().Step() = %v", d, d.Opposite().Step())
+		}
+		if got := Chebyshev(Point{}, d.Step()); got != 1 {
+			t.Errorf("%v.Step() is no unit step", d)
+		}
+		if d.Diagonal() != (Manhattan(Point{}, d.Step()) == 2) {
+			t.Errorf("%v.Diagonal() = %v", d, d.Diagonal())
+		}
+	}
+
+	for s, want := range map[string]Dir{"U": Up, "R": Right, "v": Down, "W": Left} {
+		if got, err := ParseDir(s); err != nil || got != want {
+			t.Errorf("ParseDir(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseDir("X"); err == nil {
+		t.Error("ParseDir accepted \"X\"")
+	}
+}
+
+func TestBounds(t *testing.T) {
+	check(t, func(xs, ys []small) bool {
+		var ps []Point
+		for i := 0; i < len(xs) && i < len(ys); i++ {
+			ps = append(ps, pt(xs[i], ys[i]))
+		}
+		r := Bounds(ps...)
+		if len(ps) == 0 {
+			return r.Empty()
+		}
+		for _, p := range ps {
+			if !r.Contains(p) {
+				return false
+			}
+		}
+		// every border of r touches one of the points
+		var left, right, top, bottom bool
+		for _, p := range ps {
+			left = left || p.X == r.Min.X
+			right = right || p.X == r.Max.X-1
+			top = top || p.Y == r.Min.Y
+			bottom = bottom || p.Y == r.Max.Y-1
+		}
+		return left && right && top && bottom
+	})
+}
+
+func TestRectSetOperations(t *testing.T) {
+	check(t, func(ax, ay, bx, by, cx, cy, dx, dy, px, py small) bool {
+		r, o := Bounds(pt(ax, ay), pt(bx, by)), Bounds(pt(cx, cy), pt(dx, dy))
+		p := pt(px, py)
+		u := r.Union(o)
+		return r.Intersect(o).Contains(p) == (r.Contains(p) && o.Contains(p)) &&
+			(!r.Contains(p) && !o.Contains(p) || u.Contains(p)) &&
+			u.Dx() >= max(r.Dx(), o.Dx()) && u.Dy() >= max(r.Dy(), o.Dy())
+	})
+}
+
+func TestSegment(t *testing.T) {
+	check(t, func(ax, ay, n small, dir uint8) bool {
+		a := pt(ax, ay)
+		d := Dirs8[dir%8]
+		length := int(n)%100 + 100
+		s := Seg(a, a.Add(d.Step().Mul(length)))
+
+		ps := s.Points()
+		if len(ps) != s.Len() || s.Len() != length+1 || ps[0] != s.A || ps[len(ps)-1] != s.B {
+			return false
+		}
+		for _, p := range ps {
+			if !s.Contains(p) {
+				return false
+			}
+		}
+		return !s.Contains(s.B.Add(d.Step())) && !s.Contains(s.A.Add(d.TurnRight().Step())) &&
+			s.AxisAligned() != d.Diagonal()
+	})
+}
+
+func TestDiamond(t *testing.T) {
+	check(t, func(cx, cy, r, px, py small) bool {
+		d := Diamond{pt(cx, cy), Abs(int(r)) % 1000}
+		p := d.Center.Add(pt(px%1200, py%1200))
+
+		row, ok := d.Row(p.Y)
+		inRow := ok && row.Contains(p)
+		return d.Contains(p) == inRow && (!d.Contains(p) || d.Bounds().Contains(p)) &&
+			d.Bounds().Dx() == 2*d.Radius+1
+	})
+
+	d := Diamond{Point{0, 0}, 2}
+	if got, want := d.Corners(), [4]Point{{0, -2}, {2, 0}, {0, 2}, {-2, 0}}; got != want {
+		t.Errorf("Corners() = %v, want %v", got, want)
+	}
+	if _, ok := d.Row(3); ok {
+		t.Error("Row(3) of a diamond with radius 2 exists")
+	}
+}
+
+func TestClamp(t *testing.T) {
+	r := Rect{Point{0, 0}, Point{5, 5}}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := Point{rnd.Intn(21) - 10, rnd.Intn(21) - 10}
+		c := p.Clamp(r)
+		if !r.Contains(c) || r.Contains(p) && c != p {
+			t.Fatalf("%v.Clamp(%v) = %v", p, r, c)
+		}
+	}
+}
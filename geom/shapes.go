@@ -0,0 +1,194 @@
+package geom
+
+import (
+	"fmt"
+)
+
+// ---------------------------------- Rect ------------------------------------
+
+// Rect is an axis-aligned rectangle containing the points p with
+// Min.X <= p.X < Max.X and Min.Y <= p.Y < Max.Y. A rectangle without such
+// points is empty.
+type Rect struct {
+	Min, Max Point
+}
+
+// Bounds returns the smallest rectangle containing all ps.
+func Bounds(ps ...Point) Rect {
+	var r Rect
+	for _, p := range ps {
+		r = r.Extend(p)
+	}
+	return r
+}
+
+// Dx returns the width of r.
+func (r Rect) Dx() int {
+	return r.Max.X - r.Min.X
+}
+
+// Dy returns the height of r.
+func (r Rect) Dy() int {
+	return r.Max.Y - r.Min.Y
+}
+
+func (r Rect) Empty() bool {
+	return r.Min.X >= r.Max.X || r.Min.Y >= r.Max.Y
+}
+
+func (r Rect) Contains(p Point) bool {
+	return r.Min.X <= p.X && p.X < r.Max.X && r.Min.Y <= p.Y && p.Y < r.Max.Y
+}
+
+// Extend returns the smallest rectangle containing r and p.
+func (r Rect) Extend(p Point) Rect {
+	return r.Union(Rect{p, p.Add(Point{1, 1})})
+}
+
+// Union returns the smallest rectangle containing r and o.
+func (r Rect) Union(o Rect) Rect {
+	if r.Empty() {
+		return o
+	}
+	if o.Empty() {
+		return r
+	}
+	return Rect{
+		Point{min(r.Min.X, o.Min.X), min(r.Min.Y, o.Min.Y)},
+		Point{max(r.Max.X, o.Max.X), max(r.Max.Y, o.Max.Y)},
+	}
+}
+
+// Intersect returns the largest rectangle contained in r and o. If they
+// don't overlap the empty Rect{} is returned.
+func (r Rect) Intersect(o Rect) Rect {
+	i := Rect{
+		Point{max(r.Min.X, o.Min.X), max(r.Min.Y, o.Min.Y)},
+		Point{min(r.Max.X, o.Max.X), min(r.Max.Y, o.Max.Y)},
+	}
+	if i.Empty() {
+		return Rect{}
+	}
+	return i
+}
+
+func (r Rect) String() string {
+	return fmt.Sprintf("%v-%v", r.Min, r.Max)
+}
+
+// --------------------------------- Segment ----------------------------------
+
+// Segment is the line from A to B, both ends included. Apart from Bounds
+// its methods require the segment to be horizontal, vertical or diagonal.
+type Segment struct {
+	A, B Point
+}
+
+// Seg is shorthand for Segment{a, b}.
+func Seg(a, b Point) Segment {
+	return Segment{a, b}
+}
+
+func (s Segment) Horizontal() bool {
+	return s.A.Y == s.B.Y
+}
+
+func (s Segment) Vertical() bool {
+	return s.A.X == s.B.X
+}
+
+// AxisAligned reports whether s is horizontal or vertical.
+func (s Segment) AxisAligned() bool {
+	return s.Horizontal() || s.Vertical()
+}
+
+// Diagonal reports whether s runs at 45 degrees.
+func (s Segment) Diagonal() bool {
+	d := s.B.Sub(s.A).Abs()
+	return d.X == d.Y && d.X > 0
+}
+
+func (s Segment) check() {
+	if !s.AxisAligned() && !s.Diagonal() {
+		panic(fmt.Sprintf("geom: segment %v-%v is neither axis-aligned nor diagonal", s.A, s.B))
+	}
+}
+
+// Step returns the unit step leading from A to B.
+func (s Segment) Step() Point {
+	return s.B.Sub(s.A).Sign()
+}
+
+// Len returns the number of points of s.
+func (s Segment) Len() int {
+	s.check()
+	return Chebyshev(s.A, s.B) + 1
+}
+
+// Points returns the points of s from A to B.
+func (s Segment) Points() []Point {
+	ps := make([]Point, 0, s.Len())
+	step := s.Step()
+	for p := s.A; ; p = p.Add(step) {
+		ps = append(ps, p)
+		if p == s.B {
+			return ps
+		}
+	}
+}
+
+// Contains reports whether p is one of the points of s.
+func (s Segment) Contains(p Point) bool {
+	s.check()
+	if !s.Bounds().Contains(p) {
+		return false
+	}
+	if s.AxisAligned() {
+		return true
+	}
+	d := p.Sub(s.A).Abs()
+	return d.X == d.Y
+}
+
+// Bounds returns the smallest rectangle containing s.
+func (s Segment) Bounds() Rect {
+	return Bounds(s.A, s.B)
+}
+
+// --------------------------------- Diamond ----------------------------------
+
+// Diamond is a "circle" of the Manhattan distance: the points at most Radius
+// away from Center.
+type Diamond struct {
+	Center Point
+	Radius int
+}
+
+func (d Diamond) Contains(p Point) bool {
+	return Manhattan(d.Center, p) <= d.Radius
+}
+
+// Row returns the horizontal segment of d at the given y coordinate and
+// whether d reaches that row at all.
+func (d Diamond) Row(y int) (Segment, bool) {
+	dx := d.Radius - Abs(y-d.Center.Y)
+	if dx < 0 {
+		return Segment{}, false
+	}
+	return Segment{Point{d.Center.X - dx, y}, Point{d.Center.X + dx, y}}, true
+}
+
+// Corners returns the top, right, bottom and left corner of d.
+func (d Diamond) Corners() [4]Point {
+	var corners [4]Point
+	for i, dir := range Dirs4 {
+		corners[i] = d.Center.Add(dir.Step().Mul(d.Radius))
+	}
+	return corners
+}
+
+// Bounds returns the smallest rectangle containing d.
+func (d Diamond) Bounds() Rect {
+	c := d.Corners()
+	return Bounds(c[:]...)
+}
@@ -0,0 +1,329 @@
+// Package graph provides directed, weighted graphs stored as adjacency lists
+// together with the common shortest path searches.
+//
+// The vertices of a graph with n vertices are the numbers 0 to n-1. Every
+// search takes a Filter selecting the edges it may use, so one graph can
+// serve rules like "climb at most one step" without being rebuilt.
+package graph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Inf is the distance of unreachable vertices.
+const Inf = math.MaxInt
+
+// ErrNegativeCycle is returned by BellmanFord if a negative cycle can be
+// reached from the sources.
+var ErrNegativeCycle = errors.New("graph: negative cycle")
+
+// ErrNegativeWeight is returned by Dijkstra and AStar if they reach an edge
+// with a negative weight. Such graphs need BellmanFord.
+var ErrNegativeWeight = errors.New("graph: negative weight, use BellmanFord")
+
+// ---------------------------------- Graph -----------------------------------
+
+type Edge struct {
+	From, To, Weight int
+}
+
+// Filter reports whether a search may use an edge. A nil Filter allows all
+// edges.
+type Filter func(Edge) bool
+
+func (f Filter) allows(e Edge) bool {
+	return f == nil || f(e)
+}
+
+// Graph is a directed graph with weighted edges.
+type Graph struct {
+	adj [][]Edge
+}
+
+// New returns a graph with n vertices and no edges.
+func New(n int) *Graph {
+	return &Graph{make([][]Edge, n)}
+}
+
+// Len returns the number of vertices.
+func (g *Graph) Len() int {
+	return len(g.adj)
+}
+
+func (g *Graph) check(v int) {
+	if v < 0 || v >= len(g.adj) {
+		panic(fmt.Sprintf("graph: vertex %d out of range [0, %d)", v, len(g.adj)))
+	}
+}
+
+// AddEdge adds the edge from -> to.
+func (g *Graph) AddEdge(from, to, weight int) {
+	g.check(from)
+	g.check(to)
+	g.adj[from] = append(g.adj[from], Edge{from, to, weight})
+}
+
+// AddUndirected adds the edges a -> b and b -> a.
+func (g *Graph) AddUndirected(a, b, weight int) {
+	g.AddEdge(a, b, weight)
+	g.AddEdge(b, a, weight)
+}
+
+// Edges returns the edges leaving v.
+func (g *Graph) Edges(v int) []Edge {
+	g.check(v)
+	return g.adj[v]
+}
+
+// Reverse returns the graph with the direction of all edges flipped.
+func (g *Graph) Reverse() *Graph {
+	r := New(g.Len())
+	for _, edges := range g.adj {
+		for _, e := range edges {
+			r.AddEdge(e.To, e.From, e.Weight)
+		}
+	}
+	return r
+}
+
+// ---------------------------------- Paths -----------------------------------
+
+// Paths holds the result of a search from a set of sources: the length of
+// the shortest path from the nearest source to every vertex and the vertex
+// preceding it on that path.
+type Paths struct {
+	Dist []int
+	Prev []int
+}
+
+func newPaths(g *Graph, sources []int) *Paths {
+	p := &Paths{make([]int, g.Len()), make([]int, g.Len())}
+	for v := range p.Dist {
+		p.Dist[v] = Inf
+		p.Prev[v] = -1
+	}
+	for _, s := range sources {
+		g.check(s)
+		p.Dist[s] = 0
+	}
+	return p
+}
+
+// Reachable reports whether v can be reached from one of the sources.
+func (p *Paths) Reachable(v int) bool {
+	return p.Dist[v] != Inf
+}
+
+// PathTo returns the vertices of the shortest path leading from one of the
+// sources to target, both included, or nil if target is unreachable.
+func (p *Paths) PathTo(target int) []int {
+	if !p.Reachable(target) {
+		return nil
+	}
+	var path []int
+	for v := target; v != -1; v = p.Prev[v] {
+		path = append(path, v)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// --------------------------------- Searches ---------------------------------
+
+// BFS finds the paths with the least number of edges, ignoring the weights.
+func (g *Graph) BFS(filter Filter, sources ...int) *Paths {
+	p := newPaths(g, sources)
+	queue := append([]int(nil), sources...)
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, e := range g.adj[v] {
+			if p.Dist[e.To] == Inf && filter.allows(e) {
+				p.Dist[e.To] = p.Dist[v] + 1
+				p.Prev[e.To] = v
+				queue = append(queue, e.To)
+			}
+		}
+	}
+	return p
+}
+
+// Dijkstra finds the shortest paths in a graph without negative weights. It
+// fails if it reaches an edge with a negative weight.
+func (g *Graph) Dijkstra(filter Filter, sources ...int) (*Paths, error) {
+	return g.bestFirst(filter, -1, nil, sources)
+}
+
+// AStar finds the shortest path to target in a graph without negative
+// weights. estimate must never overestimate the distance from a vertex to
+// target. If it isn't consistent as well, that is it drops by more than the
+// weight of an edge, vertices are expanded again when a shorter path to them
+// turns up. Only the path to target is final. Like Dijkstra, it fails if it
+// reaches an edge with a negative weight.
+func (g *Graph) AStar(filter Filter, target int, estimate func(v int) int, sources ...int) (*Paths, error) {
+	g.check(target)
+	return g.bestFirst(filter, target, estimate, sources)
+}
+
+type item struct {
+	v, priority int
+	// dist is the distance of v when the item was pushed.
+	dist int
+}
+
+type queue []item
+
+func (q queue) Len() int           { return len(q) }
+func (q queue) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q queue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x any)        { *q = append(*q, x.(item)) }
+func (q *queue) Pop() any {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
+
+// bestFirst expands the vertices ordered by their distance plus estimate
+// until target (if >= 0) is reached. A vertex is expanded again whenever its
+// distance improves, which only happens with inconsistent estimates. Negative
+// weights are rejected, a negative cycle would improve distances forever.
+func (g *Graph) bestFirst(filter Filter, target int, estimate func(int) int, sources []int) (*Paths, error) {
+	if estimate == nil {
+		estimate = func(int) int { return 0 }
+	}
+
+	p := newPaths(g, sources)
+	q := &queue{}
+	for _, s := range sources {
+		heap.Push(q, item{s, estimate(s), 0})
+	}
+
+	for q.Len() > 0 {
+		it := heap.Pop(q).(item)
+		v := it.v
+		if it.dist > p.Dist[v] {
+			continue // outdated entry
+		}
+		if v == target {
+			break
+		}
+
+		for _, e := range g.adj[v] {
+			if !filter.allows(e) {
+				continue
+			}
+			if e.Weight < 0 {
+				return nil, fmt.Errorf("%w: edge %d -> %d", ErrNegativeWeight, e.From, e.To)
+			}
+			if d := p.Dist[v] + e.Weight; d < p.Dist[e.To] {
+				p.Dist[e.To] = d
+				p.Prev[e.To] = v
+				heap.Push(q, item{e.To, d + estimate(e.To), d})
+			}
+		}
+	}
+	return p, nil
+}
+
+// BellmanFord finds the shortest paths in a graph which may have negative
+// weights. It fails if a negative cycle is reachable from the sources.
+func (g *Graph) BellmanFord(filter Filter, sources ...int) (*Paths, error) {
+	p := newPaths(g, sources)
+
+	relax := func() bool {
+		changed := false
+		for v, edges := range g.adj {
+			if p.Dist[v] == Inf {
+				continue
+			}
+			for _, e := range edges {
+				if d := p.Dist[v] + e.Weight; d < p.Dist[e.To] && filter.allows(e) {
+					p.Dist[e.To] = d
+					p.Prev[e.To] = v
+					changed = true
+				}
+			}
+		}
+		return changed
+	}
+
+	for i := 0; i < g.Len()-1; i++ {
+		if !relax() {
+			return p, nil
+		}
+	}
+	if relax() {
+		return nil, ErrNegativeCycle
+	}
+	return p, nil
+}
+
+// ------------------------------- All Pairs ----------------------------------
+
+// AllPairs holds the shortest distances between all pairs of vertices.
+type AllPairs struct {
+	// Dist[u][v] is the length of the shortest path from u to v.
+	Dist [][]int
+	next [][]int
+}
+
+// FloydWarshall finds the shortest paths between all pairs of vertices of
+// a graph without negative cycles in O(n³).
+func (g *Graph) FloydWarshall(filter Filter) *AllPairs {
+	n := g.Len()
+	a := &AllPairs{make([][]int, n), make([][]int, n)}
+	for u := 0; u < n; u++ {
+		a.Dist[u] = make([]int, n)
+		a.next[u] = make([]int, n)
+		for v := range a.Dist[u] {
+			a.Dist[u][v] = Inf
+			a.next[u][v] = -1
+		}
+		a.Dist[u][u] = 0
+		a.next[u][u] = u
+		for _, e := range g.adj[u] {
+			if filter.allows(e) && e.Weight < a.Dist[u][e.To] {
+				a.Dist[u][e.To] = e.Weight
+				a.next[u][e.To] = e.To
+			}
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for u := 0; u < n; u++ {
+			if a.Dist[u][k] == Inf {
+				continue
+			}
+			for v := 0; v < n; v++ {
+				if a.Dist[k][v] == Inf {
+					continue
+				}
+				if d := a.Dist[u][k] + a.Dist[k][v]; d < a.Dist[u][v] {
+					a.Dist[u][v] = d
+					a.next[u][v] = a.next[u][k]
+				}
+			}
+		}
+	}
+	return a
+}
+
+// PathTo returns the vertices of the shortest path from u to v, both
+// included, or nil if v can't be reached from u.
+func (a *AllPairs) PathTo(u, v int) []int {
+	if a.next[u][v] == -1 {
+		return nil
+	}
+	path := []int{u}
+	for u != v {
+		u = a.next[u][v]
+		path = append(path, u)
+	}
+	return path
+}
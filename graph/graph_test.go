@@ -0,0 +1,244 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// example is the graph
+//
+//	0 --4--> 1 --1--> 3
+//	|        ^        ^
+//	1        2        5
+//	v        |        |
+//	2 -------+--------+
+func example() *Graph {
+	g := New(5)
+	g.AddEdge(0, 1, 4)
+	g.AddEdge(0, 2, 1)
+	g.AddEdge(2, 1, 2)
+	g.AddEdge(1, 3, 1)
+	g.AddEdge(2, 3, 5)
+	return g
+}
+
+func TestDijkstra(t *testing.T) {
+	p, err := example().Dijkstra(nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{0, 3, 1, 4, Inf}; !reflect.DeepEqual(p.Dist, want) {
+		t.Errorf("Dist = %v, want %v", p.Dist, want)
+	}
+	if got, want := p.PathTo(3), []int{0, 2, 1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PathTo(3) = %v, want %v", got, want)
+	}
+	if got := p.PathTo(4); got != nil {
+		t.Errorf("PathTo(4) = %v, want nil", got)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	cheap := func(e Edge) bool { return e.Weight < 4 }
+	noShortcut := func(e Edge) bool { return e != Edge{2, 1, 2} }
+
+	p, err := example().Dijkstra(noShortcut, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.PathTo(3); !reflect.DeepEqual(got, []int{0, 1, 3}) {
+		t.Errorf("PathTo(3) without shortcut = %v", got)
+	}
+	if got := example().BFS(cheap, 0).Dist[3]; got != 3 {
+		t.Errorf("BFS hops to 3 over cheap edges = %d, want 3", got)
+	}
+	if got := example().BFS(nil, 0).Dist[3]; got != 2 {
+		t.Errorf("BFS hops to 3 = %d, want 2", got)
+	}
+}
+
+func TestMultipleSources(t *testing.T) {
+	p := example().BFS(nil, 1, 2)
+	if p.Dist[3] != 1 || p.Prev[1] != -1 {
+		t.Errorf("Dist = %v, Prev = %v", p.Dist, p.Prev)
+	}
+}
+
+func TestNegativeCycle(t *testing.T) {
+	g := example()
+	g.AddEdge(3, 2, -7)
+	if _, err := g.BellmanFord(nil, 0); !errors.Is(err, ErrNegativeCycle) {
+		t.Errorf("BellmanFord error = %v, want ErrNegativeCycle", err)
+	}
+	if _, err := g.Dijkstra(nil, 0); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Dijkstra error = %v, want ErrNegativeWeight", err)
+	}
+	if _, err := g.AStar(nil, 4, func(int) int { return 0 }, 0); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("AStar error = %v, want ErrNegativeWeight", err)
+	}
+
+	g = example()
+	g.AddEdge(3, 2, -3)
+	p, err := g.BellmanFord(nil, 0)
+	if err != nil || p.Dist[2] != 1 || p.Dist[4] != Inf {
+		t.Errorf("BellmanFord = %v, %v", p, err)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	r := example().Reverse()
+	p, err := r.Dijkstra(nil, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.PathTo(0); !reflect.DeepEqual(got, []int{3, 1, 2, 0}) {
+		t.Errorf("reverse PathTo(0) = %v", got)
+	}
+}
+
+func randomGraph(rnd *rand.Rand, n int, unit bool) *Graph {
+	g := New(n)
+	for i := rnd.Intn(4 * n); i > 0; i-- {
+		w := 1
+		if !unit {
+			w = rnd.Intn(10)
+		}
+		g.AddEdge(rnd.Intn(n), rnd.Intn(n), w)
+	}
+	return g
+}
+
+// checkPath verifies that path is a path of g with the given length.
+func checkPath(t *testing.T, g *Graph, path []int, from, to, length int) {
+	t.Helper()
+	if path[0] != from || path[len(path)-1] != to {
+		t.Fatalf("path %v does not lead from %d to %d", path, from, to)
+	}
+	total := 0
+	for i := 1; i < len(path); i++ {
+		best := Inf
+		for _, e := range g.Edges(path[i-1]) {
+			if e.To == path[i] && e.Weight < best {
+				best = e.Weight
+			}
+		}
+		if best == Inf {
+			t.Fatalf("path %v uses a missing edge %d -> %d", path, path[i-1], path[i])
+		}
+		total += best
+	}
+	if total != length {
+		t.Fatalf("path %v has length %d, want %d", path, total, length)
+	}
+}
+
+func TestSearchesAgree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		n := 1 + rnd.Intn(12)
+		g := randomGraph(rnd, n, i%2 == 0)
+		source, target := rnd.Intn(n), rnd.Intn(n)
+
+		dijkstra, err := g.Dijkstra(nil, source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bellmanFord, err := g.BellmanFord(nil, source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		all := g.FloydWarshall(nil)
+		astar, err := g.AStar(nil, target, func(int) int { return 0 }, source)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Random estimates below the true distance are admissible, but
+		// mostly not consistent.
+		estimates := make([]int, n)
+		for v := range estimates {
+			if d := all.Dist[v][target]; d != Inf {
+				estimates[v] = rnd.Intn(d + 1)
+			}
+		}
+		guessed, err := g.AStar(nil, target, func(v int) int { return estimates[v] }, source)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for v := 0; v < n; v++ {
+			if d := dijkstra.Dist[v]; bellmanFord.Dist[v] != d || all.Dist[source][v] != d {
+				t.Fatalf("distances to %d differ: %d, %d, %d", v, d, bellmanFord.Dist[v], all.Dist[source][v])
+			}
+			if i%2 == 0 && g.BFS(nil, source).Dist[v] != dijkstra.Dist[v] {
+				t.Fatalf("BFS distance to %d differs on unit weights", v)
+			}
+			if dijkstra.Reachable(v) {
+				checkPath(t, g, dijkstra.PathTo(v), source, v, dijkstra.Dist[v])
+				checkPath(t, g, all.PathTo(source, v), source, v, dijkstra.Dist[v])
+			} else if all.PathTo(source, v) != nil {
+				t.Fatalf("Floyd-Warshall found a path to unreachable %d", v)
+			}
+		}
+		if astar.Dist[target] != dijkstra.Dist[target] || guessed.Dist[target] != dijkstra.Dist[target] {
+			t.Fatalf("A* distances %d and %d with estimates %v, want %d",
+				astar.Dist[target], guessed.Dist[target], estimates, dijkstra.Dist[target])
+		}
+	}
+}
+
+func TestAStarHeuristic(t *testing.T) {
+	// a line 0 - 1 - ... - 9 with a detour 0 -> 10 -> 9
+	g := New(11)
+	for v := 0; v < 9; v++ {
+		g.AddUndirected(v, v+1, 1)
+	}
+	g.AddEdge(0, 10, 5)
+	g.AddEdge(10, 9, 5)
+
+	p, err := g.AStar(nil, 9, func(v int) int {
+		if v == 10 {
+			return 5
+		}
+		return 9 - v
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Dist[9] != 9 {
+		t.Errorf("Dist[9] = %d, want 9", p.Dist[9])
+	}
+	if got, want := p.PathTo(9), []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PathTo(9) = %v", got)
+	}
+}
+
+func TestAStarInconsistent(t *testing.T) {
+	g := New(5)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 3, 1)
+	g.AddEdge(0, 2, 2)
+	g.AddEdge(2, 3, 1)
+	g.AddEdge(3, 4, 3)
+
+	// The estimate of 1 is admissible, but 3 is reached via 2 first and
+	// has to be expanded again once the shorter path via 1 is found.
+	p, err := g.AStar(nil, 4, func(v int) int {
+		if v == 1 {
+			return 4
+		}
+		return 0
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Dist[4] != 5 {
+		t.Errorf("Dist[4] = %d, want 5", p.Dist[4])
+	}
+	if got, want := p.PathTo(4), []int{0, 1, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PathTo(4) = %v, want %v", got, want)
+	}
+}
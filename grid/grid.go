@@ -0,0 +1,326 @@
+// Package grid provides a generic, rectangular two-dimensional grid as used
+// by the puzzles working on maps of characters.
+//
+// The origin is the top left cell, X grows to the right and Y downwards, so
+// the first line of a character map is row 0.
+package grid
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"aoc/geom"
+	"aoc/input"
+)
+
+// ---------------------------------- Point -----------------------------------
+
+// Point is the position of a cell.
+type Point = geom.Point
+
+// The unit steps to the neighbouring cells.
+var (
+	Up        = geom.Up.Step()
+	Down      = geom.Down.Step()
+	Left      = geom.Left.Step()
+	Right     = geom.Right.Step()
+	UpLeft    = geom.UpLeft.Step()
+	UpRight   = geom.UpRight.Step()
+	DownLeft  = geom.DownLeft.Step()
+	DownRight = geom.DownRight.Step()
+)
+
+// Dirs4 are the steps to the 4-connected neighbours, Dirs8 additionally
+// contains the diagonal steps.
+var (
+	Dirs4 = []Point{Up, Right, Down, Left}
+	Dirs8 = []Point{Up, UpRight, Right, DownRight, Down, DownLeft, Left, UpLeft}
+)
+
+// ---------------------------------- Grid ------------------------------------
+
+// Grid is a Width x Height grid of cells. A grid created by Sub is a view
+// sharing the cells of its parent.
+type Grid[T any] struct {
+	Width, Height int
+	cells         []T
+	offset        int
+	stride        int
+}
+
+// New returns a grid of zero cells.
+func New[T any](width, height int) *Grid[T] {
+	if width < 0 || height < 0 {
+		panic(fmt.Sprintf("grid: negative size %dx%d", width, height))
+	}
+	return &Grid[T]{width, height, make([]T, width*height), 0, width}
+}
+
+func (g *Grid[T]) index(p Point) int {
+	if !g.In(p) {
+		panic(fmt.Sprintf("grid: %v out of %dx%d grid", p, g.Width, g.Height))
+	}
+	return g.offset + p.X + p.Y*g.stride
+}
+
+// In reports whether p lies inside the grid.
+func (g *Grid[T]) In(p Point) bool {
+	return 0 <= p.X && p.X < g.Width && 0 <= p.Y && p.Y < g.Height
+}
+
+// At returns the cell at p. It panics if p is outside of the grid.
+func (g *Grid[T]) At(p Point) T {
+	return g.cells[g.index(p)]
+}
+
+// Get returns the cell at p and whether p is inside of the grid.
+func (g *Grid[T]) Get(p Point) (T, bool) {
+	if !g.In(p) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[g.index(p)], true
+}
+
+// Set stores v at p. It panics if p is outside of the grid.
+func (g *Grid[T]) Set(p Point, v T) {
+	g.cells[g.index(p)] = v
+}
+
+// Each calls f for every cell, row by row.
+func (g *Grid[T]) Each(f func(p Point, v T)) {
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			p := geom.Pt(x, y)
+			f(p, g.At(p))
+		}
+	}
+}
+
+// Find returns the first cell, row by row, satisfying match.
+func (g *Grid[T]) Find(match func(T) bool) (Point, bool) {
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if p := geom.Pt(x, y); match(g.At(p)) {
+				return p, true
+			}
+		}
+	}
+	return Point{}, false
+}
+
+// Count returns the number of cells satisfying match.
+func (g *Grid[T]) Count(match func(T) bool) int {
+	n := 0
+	g.Each(func(_ Point, v T) {
+		if match(v) {
+			n++
+		}
+	})
+	return n
+}
+
+// Clone returns a copy of g not sharing its cells.
+func (g *Grid[T]) Clone() *Grid[T] {
+	c := New[T](g.Width, g.Height)
+	g.Each(c.Set)
+	return c
+}
+
+// Sub returns the view of the w x h cells starting at p. Changes to the
+// view are visible in g and vice versa.
+func (g *Grid[T]) Sub(p Point, w, h int) *Grid[T] {
+	if w < 0 || h < 0 || p.X < 0 || p.Y < 0 || p.X+w > g.Width || p.Y+h > g.Height {
+		panic(fmt.Sprintf("grid: %dx%d view at %v out of %dx%d grid", w, h, p, g.Width, g.Height))
+	}
+	return &Grid[T]{w, h, g.cells, g.offset + p.X + p.Y*g.stride, g.stride}
+}
+
+// ------------------------------- Neighbours ---------------------------------
+
+func (g *Grid[T]) neighbours(p Point, dirs []Point) []Point {
+	ps := make([]Point, 0, len(dirs))
+	for _, d := range dirs {
+		if q := p.Add(d); g.In(q) {
+			ps = append(ps, q)
+		}
+	}
+	return ps
+}
+
+// Neighbours4 returns the horizontally and vertically adjacent cells of p
+// inside of the grid.
+func (g *Grid[T]) Neighbours4(p Point) []Point {
+	return g.neighbours(p, Dirs4)
+}
+
+// Neighbours8 returns the cells inside of the grid surrounding p, diagonal
+// ones included.
+func (g *Grid[T]) Neighbours8(p Point) []Point {
+	return g.neighbours(p, Dirs8)
+}
+
+// ---------------------------------- Rays ------------------------------------
+
+// Ray returns the cells seen from p looking into direction dir, nearest
+// first, up to the border of the grid. p itself is not part of the ray.
+func (g *Grid[T]) Ray(p, dir Point) []Point {
+	if dir == (Point{}) {
+		panic("grid: ray without direction")
+	}
+	var ps []Point
+	for q := p.Add(dir); g.In(q); q = q.Add(dir) {
+		ps = append(ps, q)
+	}
+	return ps
+}
+
+// Walk calls f for the cells of the ray from p into direction dir until f
+// returns false. It reports whether the border of the grid was reached.
+func (g *Grid[T]) Walk(p, dir Point, f func(p Point, v T) bool) bool {
+	for _, q := range g.Ray(p, dir) {
+		if !f(q, g.At(q)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Row returns a copy of the cells of row y.
+func (g *Grid[T]) Row(y int) []T {
+	return g.values(g.Ray(geom.Pt(-1, y), Right))
+}
+
+// Col returns a copy of the cells of column x.
+func (g *Grid[T]) Col(x int) []T {
+	return g.values(g.Ray(geom.Pt(x, -1), Down))
+}
+
+func (g *Grid[T]) values(ps []Point) []T {
+	vs := make([]T, len(ps))
+	for i, p := range ps {
+		vs[i] = g.At(p)
+	}
+	return vs
+}
+
+// -------------------------------- Rendering ---------------------------------
+
+// Render draws the grid with one character per cell as returned by cell.
+func (g *Grid[T]) Render(cell func(p Point, v T) rune) string {
+	var sb strings.Builder
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			p := geom.Pt(x, y)
+			sb.WriteRune(cell(p, g.At(p)))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// String draws the grid with one character per cell: runes as themselves,
+// booleans as '#' and '.', digits as digits and other values by the first
+// character of their default format.
+func (g *Grid[T]) String() string {
+	return g.Render(func(_ Point, v T) rune {
+		return format(v)
+	})
+}
+
+func format(v any) rune {
+	switch v := v.(type) {
+	case rune:
+		return v
+	case byte:
+		return rune(v)
+	case bool:
+		if v {
+			return '#'
+		}
+		return '.'
+	case int:
+		if 0 <= v && v <= 9 {
+			return rune('0' + v)
+		}
+	}
+	r, _ := utf8.DecodeRuneInString(fmt.Sprint(v))
+	return r
+}
+
+// --------------------------------- Parsing ----------------------------------
+
+// Parse reads a character map from r, converting every character with
+// cell. All rows need to have the same length. A ParseError returned by
+// cell is reported at the position of the character; other errors are
+// wrapped into one.
+func Parse[T any](r io.Reader, cell func(p Point, c rune) (T, error)) (*Grid[T], error) {
+	var (
+		cells []T
+		width int
+		y     int
+	)
+
+	err := input.EachLine(r, func(line string) error {
+		x := 0
+		for col, c := range line {
+			v, err := cell(geom.Pt(x, y), c)
+			if err != nil {
+				return atColumn(col+1, string(c), err)
+			}
+			cells = append(cells, v)
+			x++
+		}
+		if x == 0 {
+			return input.Errorf("empty row")
+		}
+		if y > 0 && x != width {
+			return input.Errorf("expected %d columns, got %d", width, x)
+		}
+		width = x
+		y++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if y == 0 {
+		return nil, errors.New("empty grid")
+	}
+
+	return &Grid[T]{width, y, cells, 0, width}, nil
+}
+
+func atColumn(col int, text string, err error) error {
+	var pe *input.ParseError
+	if !errors.As(err, &pe) {
+		return &input.ParseError{Column: col, Text: text, Msg: err.Error(), Err: err}
+	}
+	if pe.Column == 0 {
+		pe.Column = col
+		if pe.Text == "" {
+			pe.Text = text
+		}
+	}
+	return err
+}
+
+// Runes reads a character map from r.
+func Runes(r io.Reader) (*Grid[rune], error) {
+	return Parse(r, func(_ Point, c rune) (rune, error) {
+		return c, nil
+	})
+}
+
+// Digits reads a map of single decimal digits from r.
+func Digits(r io.Reader) (*Grid[int], error) {
+	return Parse(r, func(_ Point, c rune) (int, error) {
+		if c < '0' || c > '9' {
+			return 0, input.Errorf("invalid digit")
+		}
+		return int(c - '0'), nil
+	})
+}
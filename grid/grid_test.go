@@ -0,0 +1,175 @@
+package grid
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"aoc/geom"
+	"aoc/input"
+)
+
+const example = `abc
+def
+ghi
+`
+
+func mustRunes(t *testing.T, s string) *Grid[rune] {
+	t.Helper()
+	g, err := Runes(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestParse(t *testing.T) {
+	g := mustRunes(t, example)
+	if g.Width != 3 || g.Height != 3 {
+		t.Fatalf("size = %dx%d, want 3x3", g.Width, g.Height)
+	}
+	if got := g.At(geom.Pt(2, 1)); got != 'f' {
+		t.Errorf("At(2,1) = %q, want 'f'", got)
+	}
+	if got := g.String(); got != example {
+		t.Errorf("String() = %q, want %q", got, example)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input        string
+		line, column int
+	}{
+		{"12\n3\n", 2, 0},
+		{"12\n\n", 2, 0},
+		{"12\n3x\n", 2, 2},
+	}
+
+	for _, tt := range tests {
+		_, err := Digits(strings.NewReader(tt.input))
+		var pe *input.ParseError
+		if !errors.As(err, &pe) || pe.Line != tt.line || pe.Column != tt.column {
+			t.Errorf("Digits(%q) error = %v, want parse error at %d:%d", tt.input, err, tt.line, tt.column)
+		}
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	g := New[int](3, 3)
+	tests := []struct {
+		p      Point
+		n4, n8 int
+	}{
+		{geom.Pt(0, 0), 2, 3},
+		{geom.Pt(1, 0), 3, 5},
+		{geom.Pt(1, 1), 4, 8},
+		{geom.Pt(2, 2), 2, 3},
+	}
+
+	for _, tt := range tests {
+		if got := len(g.Neighbours4(tt.p)); got != tt.n4 {
+			t.Errorf("len(Neighbours4(%v)) = %d, want %d", tt.p, got, tt.n4)
+		}
+		if got := len(g.Neighbours8(tt.p)); got != tt.n8 {
+			t.Errorf("len(Neighbours8(%v)) = %d, want %d", tt.p, got, tt.n8)
+		}
+	}
+}
+
+func TestRays(t *testing.T) {
+	g := mustRunes(t, example)
+
+	tests := []struct {
+		p, dir Point
+		want   string
+	}{
+		{geom.Pt(0, 0), Right, "bc"},
+		{geom.Pt(1, 1), Up, "b"},
+		{geom.Pt(1, 1), Left, "d"},
+		{geom.Pt(0, 0), DownRight, "ei"},
+		{geom.Pt(0, 2), UpRight, "ec"},
+		{geom.Pt(2, 2), Down, ""},
+	}
+
+	for _, tt := range tests {
+		if got := string(g.values(g.Ray(tt.p, tt.dir))); got != tt.want {
+			t.Errorf("Ray(%v, %v) = %q, want %q", tt.p, tt.dir, got, tt.want)
+		}
+	}
+
+	if got := string(g.Row(1)); got != "def" {
+		t.Errorf("Row(1) = %q, want \"def\"", got)
+	}
+	if got := string(g.Col(2)); got != "cfi" {
+		t.Errorf("Col(2) = %q, want \"cfi\"", got)
+	}
+
+	var seen []rune
+	reached := g.Walk(geom.Pt(0, 1), Right, func(_ Point, v rune) bool {
+		seen = append(seen, v)
+		return v != 'e'
+	})
+	if reached || string(seen) != "e" {
+		t.Errorf("Walk stopping at 'e' = %v, saw %q", reached, string(seen))
+	}
+}
+
+func TestSub(t *testing.T) {
+	g := mustRunes(t, example)
+	sub := g.Sub(geom.Pt(1, 1), 2, 2)
+
+	if got := sub.String(); got != "ef\nhi\n" {
+		t.Errorf("Sub.String() = %q", got)
+	}
+	if sub.In(geom.Pt(2, 0)) {
+		t.Error("view contains a cell right of it")
+	}
+
+	sub.Set(geom.Pt(0, 0), 'E')
+	if got := g.At(geom.Pt(1, 1)); got != 'E' {
+		t.Errorf("view write not visible in grid, got %q", got)
+	}
+
+	c := g.Clone()
+	c.Set(geom.Pt(0, 0), 'A')
+	if g.At(geom.Pt(0, 0)) != 'a' {
+		t.Error("clone shares cells with grid")
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := New[bool](3, 2)
+	g.Set(geom.Pt(1, 0), true)
+	if got := g.String(); got != ".#.\n...\n" {
+		t.Errorf("String() = %q", got)
+	}
+
+	got := g.Render(func(p Point, v bool) rune {
+		if p == (geom.Pt(2, 1)) {
+			return 'o'
+		}
+		return format(v)
+	})
+	if got != ".#.\n..o\n" {
+		t.Errorf("Render() = %q", got)
+	}
+
+	if p, ok := g.Find(func(v bool) bool { return v }); !ok || p != (geom.Pt(1, 0)) {
+		t.Errorf("Find = %v, %v", p, ok)
+	}
+	if n := g.Count(func(v bool) bool { return !v }); n != 5 {
+		t.Errorf("Count = %d, want 5", n)
+	}
+}
+
+func TestDigits(t *testing.T) {
+	g, err := Digits(strings.NewReader("30\n25\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Row(1), []int{2, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Row(1) = %v, want %v", got, want)
+	}
+}
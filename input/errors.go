@@ -0,0 +1,66 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseError describes malformed puzzle input. Line and Column are 1-based
+// and 0 if unknown; Column counts bytes like the positions reported by the
+// go compiler.
+type ParseError struct {
+	Line, Column int
+	// Text is the offending part of the line.
+	Text string
+	Msg  string
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	var sb strings.Builder
+	if e.Line > 0 {
+		fmt.Fprintf(&sb, "line %d", e.Line)
+		if e.Column > 0 {
+			fmt.Fprintf(&sb, ", column %d", e.Column)
+		}
+		sb.WriteString(": ")
+	}
+	sb.WriteString(e.Message())
+	return sb.String()
+}
+
+// Message returns the error without its position.
+func (e *ParseError) Message() string {
+	if e.Text == "" {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s: %q", e.Msg, e.Text)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Errorf returns a ParseError concerning the whole current line. The line
+// number is filled in by the readers of this package.
+func Errorf(format string, args ...any) *ParseError {
+	return &ParseError{Msg: fmt.Sprintf(format, args...)}
+}
+
+// atLine attaches the line number to err. Errors which are no ParseError
+// are wrapped into one.
+func atLine(line int, err error) error {
+	if err == nil {
+		return nil
+	}
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		if pe.Line == 0 {
+			pe.Line = line
+		}
+		return err
+	}
+	return &ParseError{Line: line, Msg: err.Error(), Err: err}
+}
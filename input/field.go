@@ -0,0 +1,121 @@
+package input
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field is a part of a line that remembers the column it starts at, so
+// errors about it can point to the exact position.
+type Field struct {
+	Text   string
+	Column int
+}
+
+// Line returns the whole line as a field.
+func Line(line string) Field {
+	return Field{line, 1}
+}
+
+// Split splits line at every sep.
+func Split(line, sep string) []Field {
+	return Line(line).Split(sep)
+}
+
+// Fields splits line around runs of white space.
+func Fields(line string) []Field {
+	return Line(line).Fields()
+}
+
+// Split splits f at every sep.
+func (f Field) Split(sep string) []Field {
+	var (
+		fields []Field
+		col    = f.Column
+	)
+	for _, s := range strings.Split(f.Text, sep) {
+		fields = append(fields, Field{s, col})
+		col += len(s) + len(sep)
+	}
+	return fields
+}
+
+// Fields splits f around runs of white space.
+func (f Field) Fields() []Field {
+	var fields []Field
+	start := -1
+	for i, r := range f.Text + " " {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			fields = append(fields, Field{f.Text[start:i], f.Column + start})
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	return fields
+}
+
+// Cut slices f around the first instance of sep.
+func (f Field) Cut(sep string) (before, after Field, found bool) {
+	b, a, found := strings.Cut(f.Text, sep)
+	return Field{b, f.Column}, Field{a, f.Column + len(b) + len(sep)}, found
+}
+
+// TrimSpace removes leading and trailing white space.
+func (f Field) TrimSpace() Field {
+	trimmed := strings.TrimLeftFunc(f.Text, unicode.IsSpace)
+	col := f.Column + len(f.Text) - len(trimmed)
+	return Field{strings.TrimRightFunc(trimmed, unicode.IsSpace), col}
+}
+
+// Int converts f to an integer.
+func (f Field) Int() (int, error) {
+	n, err := strconv.Atoi(f.Text)
+	if err == nil {
+		return n, nil
+	}
+	msg := "invalid integer"
+	if errors.Is(err, strconv.ErrRange) {
+		msg = "integer out of range"
+	}
+	return 0, &ParseError{Column: f.Column, Text: f.Text, Msg: msg, Err: err}
+}
+
+// Errorf returns a ParseError pointing to f.
+func (f Field) Errorf(format string, args ...any) *ParseError {
+	err := Errorf(format, args...)
+	err.Column = f.Column
+	err.Text = f.Text
+	return err
+}
+
+// Expect returns an error if f is not the text want.
+func (f Field) Expect(want string) error {
+	if f.Text != want {
+		return f.Errorf("expected %q", want)
+	}
+	return nil
+}
+
+// Submatches returns the capture groups of re in line. Lines not matching
+// re are reported as error.
+func Submatches(re *regexp.Regexp, line string) ([]Field, error) {
+	idx := re.FindStringSubmatchIndex(line)
+	if idx == nil {
+		return nil, &ParseError{Column: 1, Text: line, Msg: "malformed line, expected " + re.String()}
+	}
+
+	fields := make([]Field, 0, len(idx)/2-1)
+	for i := 2; i < len(idx); i += 2 {
+		if idx[i] < 0 {
+			fields = append(fields, Field{})
+			continue
+		}
+		fields = append(fields, Field{line[idx[i]:idx[i+1]], idx[i] + 1})
+	}
+	return fields, nil
+}
@@ -0,0 +1,206 @@
+// Package input contains the readers shared by all puzzle solutions.
+//
+// Every reader works on an arbitrary io.Reader, accepts lines far longer
+// than the default bufio.Scanner limit and reports malformed input as
+// ParseError together with the (1-based) number of the offending line.
+package input
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"regexp"
+)
+
+// MaxLineLength is the longest line the readers accept.
+const MaxLineLength = 16 * 1024 * 1024
+
+// --------------------------------- Lines ----------------------------------
+
+// NewScanner returns a line scanner for r with a raised buffer limit.
+func NewScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLineLength)
+	return scanner
+}
+
+// EachLine calls f for every line of r. An error returned by f stops the
+// iteration and is reported as ParseError of the current line.
+func EachLine(r io.Reader, f func(line string) error) error {
+	scanner := NewScanner(r)
+	n := 0
+
+	for scanner.Scan() {
+		n++
+		if err := f(scanner.Text()); err != nil {
+			return atLine(n, err)
+		}
+	}
+
+	err := scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		return &ParseError{Line: n + 1, Msg: "line too long", Err: err}
+	}
+	return err
+}
+
+// EachLineDo calls f for every line of r.
+func EachLineDo(r io.Reader, f func(string)) error {
+	return EachLine(r, func(line string) error {
+		f(line)
+		return nil
+	})
+}
+
+// Lines reads all lines of r.
+func Lines(r io.Reader) ([]string, error) {
+	var lines []string
+	err := EachLineDo(r, func(line string) {
+		lines = append(lines, line)
+	})
+	return lines, err
+}
+
+// --------------------------------- Blocks ---------------------------------
+
+// EachBlock calls f for every group of lines of r separated by one or more
+// blank lines. The line number of a ParseError returned by f is taken as
+// relative to the first line of the block; other errors are reported on the
+// first line of the block.
+func EachBlock(r io.Reader, f func(block []string) error) error {
+	var (
+		block []string
+		start int
+	)
+
+	flush := func() error {
+		if len(block) == 0 {
+			return nil
+		}
+		err := f(block)
+		block = nil
+
+		var pe *ParseError
+		if errors.As(err, &pe) && pe.Line > 0 {
+			pe.Line += start - 1
+		}
+		return atLine(start, err)
+	}
+
+	n := 0
+	err := EachLine(r, func(line string) error {
+		n++
+		if len(line) == 0 {
+			return flush()
+		}
+		if len(block) == 0 {
+			start = n
+		}
+		block = append(block, line)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// Blocks reads all blank-line-separated groups of lines of r.
+func Blocks(r io.Reader) ([][]string, error) {
+	var blocks [][]string
+	err := EachBlock(r, func(block []string) error {
+		blocks = append(blocks, block)
+		return nil
+	})
+	return blocks, err
+}
+
+// ---------------------------------- Grid ----------------------------------
+
+// Grid reads r as a rectangular map of characters. All rows need to have the
+// same length.
+func Grid(r io.Reader) ([][]rune, error) {
+	var grid [][]rune
+	err := EachLine(r, func(line string) error {
+		row := []rune(line)
+		if len(grid) > 0 && len(row) != len(grid[0]) {
+			return Errorf("expected %d columns, got %d", len(grid[0]), len(row))
+		}
+		grid = append(grid, row)
+		return nil
+	})
+	return grid, err
+}
+
+// ---------------------------------- Ints ----------------------------------
+
+// Ints reads one integer per line.
+func Ints(r io.Reader) ([]int, error) {
+	var ns []int
+	err := EachLine(r, func(line string) error {
+		n, err := Line(line).TrimSpace().Int()
+		if err != nil {
+			return err
+		}
+		ns = append(ns, n)
+		return nil
+	})
+	return ns, err
+}
+
+// ParseInts converts every line of a block to an integer. Errors report the
+// line relative to the block, see EachBlock.
+func ParseInts(lines []string) ([]int, error) {
+	ns := make([]int, 0, len(lines))
+	for i, line := range lines {
+		n, err := Line(line).TrimSpace().Int()
+		if err != nil {
+			return nil, atLine(i+1, err)
+		}
+		ns = append(ns, n)
+	}
+	return ns, nil
+}
+
+// IntList parses a list of integers separated by sep, e.g. "79, 98" with
+// sep ",". Surrounding white space of each element is ignored.
+func IntList(f Field, sep string) ([]int, error) {
+	var ns []int
+	for _, field := range f.Split(sep) {
+		n, err := field.TrimSpace().Int()
+		if err != nil {
+			return nil, err
+		}
+		ns = append(ns, n)
+	}
+	return ns, nil
+}
+
+// --------------------------------- Regexp ---------------------------------
+
+// EachMatch calls f with the capture groups of re for every line of r. Lines
+// not matching re are reported as error.
+func EachMatch(r io.Reader, re *regexp.Regexp, f func(groups []Field) error) error {
+	return EachLine(r, func(line string) error {
+		groups, err := Submatches(re, line)
+		if err != nil {
+			return err
+		}
+		return f(groups)
+	})
+}
+
+// Matches reads the capture groups of re for every line of r.
+func Matches(r io.Reader, re *regexp.Regexp) ([][]string, error) {
+	var all [][]string
+	err := EachMatch(r, re, func(groups []Field) error {
+		texts := make([]string, len(groups))
+		for i, group := range groups {
+			texts[i] = group.Text
+		}
+		all = append(all, texts)
+		return nil
+	})
+	return all, err
+}
@@ -0,0 +1,152 @@
+package input
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestBlocks(t *testing.T) {
+	blocks, err := Blocks(strings.NewReader("1\n2\n\n3\n\n\n4\n5\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"1", "2"}, {"3"}, {"4", "5"}}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Errorf("Blocks = %q, want %q", blocks, want)
+	}
+}
+
+func TestEachBlockErrorLine(t *testing.T) {
+	err := EachBlock(strings.NewReader("1\n\n2\n3\n"), func(block []string) error {
+		if block[0] == "2" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 3 {
+		t.Errorf("EachBlock error = %v, want error on line 3", err)
+	}
+}
+
+func TestIntsErrorLine(t *testing.T) {
+	_, err := Ints(strings.NewReader("1\n2\nthree\n"))
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 3 {
+		t.Errorf("Ints error = %v, want error on line 3", err)
+	}
+}
+
+func TestGrid(t *testing.T) {
+	grid, err := Grid(strings.NewReader("ab\ncd\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]rune{{'a', 'b'}, {'c', 'd'}}; !reflect.DeepEqual(grid, want) {
+		t.Errorf("Grid = %q, want %q", grid, want)
+	}
+
+	if _, err := Grid(strings.NewReader("ab\nc\n")); err == nil {
+		t.Error("Grid accepted rows of different length")
+	}
+}
+
+func TestIntList(t *testing.T) {
+	ns, err := IntList(Line("79, 98, -3"), ",")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{79, 98, -3}; !reflect.DeepEqual(ns, want) {
+		t.Errorf("IntList = %v, want %v", ns, want)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	re := regexp.MustCompile(`^(\w+) (\d+)$`)
+	matches, err := Matches(strings.NewReader("R 4\nU 12\n"), re)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"R", "4"}, {"U", "12"}}; !reflect.DeepEqual(matches, want) {
+		t.Errorf("Matches = %q, want %q", matches, want)
+	}
+
+	_, err = Matches(strings.NewReader("R 4\nfoo\n"), re)
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 2 {
+		t.Errorf("Matches error = %v, want error on line 2", err)
+	}
+}
+
+func TestLongLine(t *testing.T) {
+	long := strings.Repeat("x", 1<<20)
+	lines, err := Lines(strings.NewReader(long + "\nshort\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 || lines[0] != long {
+		t.Errorf("Lines did not return the long line")
+	}
+}
+
+func TestIntsColumn(t *testing.T) {
+	_, err := IntList(Line("Starting items: 79, x8"), ",")
+	if err == nil {
+		t.Fatal("IntList accepted invalid integer")
+	}
+
+	_, after, _ := Line("Starting items: 79, x8").Cut(":")
+	_, err = IntList(after, ",")
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Column != 21 || pe.Text != "x8" {
+		t.Errorf("IntList error = %#v, want column 21 and text x8", err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	fields := Fields("  move 1  from 2")
+	want := []Field{{"move", 3}, {"1", 8}, {"from", 11}, {"2", 16}}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Fields = %v, want %v", fields, want)
+	}
+
+	if got := Split("a-bc-d", "-")[2]; got != (Field{"d", 6}) {
+		t.Errorf("Split = %v, want d at column 6", got)
+	}
+	if got := (Field{"  x ", 4}).TrimSpace(); got != (Field{"x", 6}) {
+		t.Errorf("TrimSpace = %v, want x at column 6", got)
+	}
+}
+
+func TestSubmatches(t *testing.T) {
+	re := regexp.MustCompile(`x=(-?\d+), y=(-?\d+)`)
+	fields, err := Submatches(re, "at x=-12, y=7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Field{{"-12", 6}, {"7", 13}}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Submatches = %v, want %v", fields, want)
+	}
+}
+
+func TestParseErrorInBlock(t *testing.T) {
+	err := EachBlock(strings.NewReader("1\n\n2\n3\nx\n"), func(block []string) error {
+		_, err := ParseInts(block)
+		return err
+	})
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 5 || pe.Column != 1 || pe.Text != "x" {
+		t.Errorf("EachBlock error = %v, want invalid integer on line 5", err)
+	}
+	if want := `line 5, column 1: invalid integer: "x"`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,241 @@
+// Package interval provides closed integer intervals and sets of integers
+// stored as sorted, disjoint intervals.
+package interval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// -------------------------------- Interval ----------------------------------
+
+// Interval is the closed range of integers Lo <= x <= Hi. It is empty if
+// Lo > Hi.
+type Interval struct {
+	Lo, Hi int
+}
+
+// New returns the interval [lo, hi].
+func New(lo, hi int) Interval {
+	return Interval{lo, hi}
+}
+
+func (iv Interval) Empty() bool {
+	return iv.Lo > iv.Hi
+}
+
+// Len returns the number of integers in iv.
+func (iv Interval) Len() int {
+	if iv.Empty() {
+		return 0
+	}
+	return iv.Hi - iv.Lo + 1
+}
+
+// Contains reports whether x lies in iv.
+func (iv Interval) Contains(x int) bool {
+	return iv.Lo <= x && x <= iv.Hi
+}
+
+// Covers reports whether all of o lies in iv. Every interval covers the
+// empty interval.
+func (iv Interval) Covers(o Interval) bool {
+	return o.Empty() || iv.Lo <= o.Lo && o.Hi <= iv.Hi
+}
+
+// Overlaps reports whether iv and o have an integer in common.
+func (iv Interval) Overlaps(o Interval) bool {
+	return !iv.Intersect(o).Empty()
+}
+
+// Intersect returns the integers both in iv and o.
+func (iv Interval) Intersect(o Interval) Interval {
+	return Interval{max(iv.Lo, o.Lo), min(iv.Hi, o.Hi)}
+}
+
+func (iv Interval) String() string {
+	if iv.Empty() {
+		return "[]"
+	}
+	return fmt.Sprintf("[%d,%d]", iv.Lo, iv.Hi)
+}
+
+// ----------------------------------- Set ------------------------------------
+
+// Set is a set of integers. It keeps its members as sorted list of
+// disjoint intervals, merging overlapping and adjacent intervals on insert.
+// The zero value is an empty set.
+type Set struct {
+	ivs []Interval
+}
+
+// NewSet returns the union of ivs.
+func NewSet(ivs ...Interval) *Set {
+	s := &Set{}
+	for _, iv := range ivs {
+		s.Add(iv)
+	}
+	return s
+}
+
+// Intervals returns the disjoint intervals of s in ascending order.
+func (s *Set) Intervals() []Interval {
+	return append([]Interval(nil), s.ivs...)
+}
+
+// Clone returns a copy of s.
+func (s *Set) Clone() *Set {
+	return &Set{s.Intervals()}
+}
+
+// Clear removes all members of s, keeping its storage.
+func (s *Set) Clear() {
+	s.ivs = s.ivs[:0]
+}
+
+// Empty reports whether s has no members.
+func (s *Set) Empty() bool {
+	return len(s.ivs) == 0
+}
+
+// Len returns the number of integers in s.
+func (s *Set) Len() int {
+	n := 0
+	for _, iv := range s.ivs {
+		n += iv.Len()
+	}
+	return n
+}
+
+// search returns the index of the first interval ending at or after x.
+func (s *Set) search(x int) int {
+	return sort.Search(len(s.ivs), func(i int) bool {
+		return s.ivs[i].Hi >= x
+	})
+}
+
+// replace replaces s.ivs[i:j] by ivs.
+func (s *Set) replace(i, j int, ivs ...Interval) {
+	tail := append(ivs, s.ivs[j:]...)
+	s.ivs = append(s.ivs[:i], tail...)
+}
+
+// Add inserts all integers of iv into s.
+func (s *Set) Add(iv Interval) {
+	if iv.Empty() {
+		return
+	}
+
+	// merge with every interval overlapping or adjacent to iv
+	i := s.search(iv.Lo - 1)
+	j := i
+	for ; j < len(s.ivs) && s.ivs[j].Lo <= iv.Hi+1; j++ {
+		iv.Lo = min(iv.Lo, s.ivs[j].Lo)
+		iv.Hi = max(iv.Hi, s.ivs[j].Hi)
+	}
+	s.replace(i, j, iv)
+}
+
+// Remove deletes all integers of iv from s.
+func (s *Set) Remove(iv Interval) {
+	if iv.Empty() {
+		return
+	}
+
+	i := s.search(iv.Lo)
+	j := i
+	var rest []Interval
+	for ; j < len(s.ivs) && s.ivs[j].Lo <= iv.Hi; j++ {
+		cur := s.ivs[j]
+		if cur.Lo < iv.Lo {
+			rest = append(rest, Interval{cur.Lo, iv.Lo - 1})
+		}
+		if cur.Hi > iv.Hi {
+			rest = append(rest, Interval{iv.Hi + 1, cur.Hi})
+		}
+	}
+	s.replace(i, j, rest...)
+}
+
+// Find returns the interval of s containing x.
+func (s *Set) Find(x int) (Interval, bool) {
+	if i := s.search(x); i < len(s.ivs) && s.ivs[i].Contains(x) {
+		return s.ivs[i], true
+	}
+	return Interval{}, false
+}
+
+// Contains reports whether x is a member of s.
+func (s *Set) Contains(x int) bool {
+	_, ok := s.Find(x)
+	return ok
+}
+
+// Covers reports whether all integers of iv are members of s.
+func (s *Set) Covers(iv Interval) bool {
+	if iv.Empty() {
+		return true
+	}
+	found, ok := s.Find(iv.Lo)
+	return ok && found.Covers(iv)
+}
+
+// Union returns the integers in s or o.
+func (s *Set) Union(o *Set) *Set {
+	u := s.Clone()
+	for _, iv := range o.ivs {
+		u.Add(iv)
+	}
+	return u
+}
+
+// Intersect returns the integers in both s and o.
+func (s *Set) Intersect(o *Set) *Set {
+	var ivs []Interval
+	for i, j := 0, 0; i < len(s.ivs) && j < len(o.ivs); {
+		if iv := s.ivs[i].Intersect(o.ivs[j]); !iv.Empty() {
+			ivs = append(ivs, iv)
+		}
+		if s.ivs[i].Hi < o.ivs[j].Hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return &Set{ivs}
+}
+
+// Subtract returns the integers in s but not in o.
+func (s *Set) Subtract(o *Set) *Set {
+	d := s.Clone()
+	for _, iv := range o.ivs {
+		d.Remove(iv)
+	}
+	return d
+}
+
+// Gaps returns the intervals of within which are not covered by s in
+// ascending order.
+func (s *Set) Gaps(within Interval) []Interval {
+	var gaps []Interval
+	next := within.Lo
+	for i := s.search(within.Lo); i < len(s.ivs) && s.ivs[i].Lo <= within.Hi; i++ {
+		if gap := (Interval{next, s.ivs[i].Lo - 1}); !gap.Empty() {
+			gaps = append(gaps, gap)
+		}
+		next = s.ivs[i].Hi + 1
+	}
+	if gap := (Interval{next, within.Hi}); !gap.Empty() {
+		gaps = append(gaps, gap)
+	}
+	return gaps
+}
+
+func (s *Set) String() string {
+	strs := make([]string, len(s.ivs))
+	for i, iv := range s.ivs {
+		strs[i] = iv.String()
+	}
+	return "{" + strings.Join(strs, " ") + "}"
+}
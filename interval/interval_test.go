@@ -0,0 +1,135 @@
+package interval
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestInterval(t *testing.T) {
+	a, b := New(2, 8), New(3, 7)
+	if !a.Covers(b) || b.Covers(a) {
+		t.Errorf("%v.Covers(%v) = %v, %v.Covers(%v) = %v", a, b, a.Covers(b), b, a, b.Covers(a))
+	}
+	if !New(5, 7).Overlaps(New(7, 9)) || New(2, 4).Overlaps(New(6, 8)) {
+		t.Error("Overlaps misses a common end or sees a gap as overlap")
+	}
+	if got := New(4, 6).Len(); got != 3 {
+		t.Errorf("Len() = %d, want 3", got)
+	}
+	if got := New(4, 3).Len(); got != 0 {
+		t.Errorf("Len() of empty interval = %d", got)
+	}
+}
+
+func TestAddMerges(t *testing.T) {
+	s := NewSet(New(1, 3), New(10, 12), New(5, 6))
+	s.Add(New(4, 4)) // adjacent on both sides
+	s.Add(New(11, 20))
+
+	want := []Interval{{1, 6}, {10, 20}}
+	if got := s.Intervals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Intervals() = %v, want %v", got, want)
+	}
+	if got := s.Len(); got != 17 {
+		t.Errorf("Len() = %d, want 17", got)
+	}
+}
+
+func TestGaps(t *testing.T) {
+	s := NewSet(New(-5, 2), New(4, 9), New(15, 30))
+
+	want := []Interval{{3, 3}, {10, 14}}
+	if got := s.Gaps(New(0, 20)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Gaps([0,20]) = %v, want %v", got, want)
+	}
+	if got := s.Gaps(New(10, 12)); !reflect.DeepEqual(got, []Interval{{10, 12}}) {
+		t.Errorf("Gaps([10,12]) = %v", got)
+	}
+	if got := s.Gaps(New(4, 9)); got != nil {
+		t.Errorf("Gaps([4,9]) = %v, want none", got)
+	}
+}
+
+// model is the reference implementation of a set of integers.
+type model map[int]bool
+
+const lo, hi = -30, 30
+
+func randomInterval(rnd *rand.Rand) Interval {
+	a := lo + rnd.Intn(hi-lo+1)
+	return New(a, a+rnd.Intn(10)-1)
+}
+
+// check compares s with m and verifies the invariants of s.
+func check(t *testing.T, s *Set, m model) {
+	t.Helper()
+	for i, iv := range s.ivs {
+		if iv.Empty() || i > 0 && s.ivs[i-1].Hi+1 >= iv.Lo {
+			t.Fatalf("%v: intervals not sorted, disjoint and separated", s)
+		}
+	}
+	n := 0
+	for x := lo - 15; x <= hi+15; x++ {
+		if s.Contains(x) != m[x] {
+			t.Fatalf("%v: Contains(%d) = %v, want %v", s, x, s.Contains(x), m[x])
+		}
+		if m[x] {
+			n++
+		}
+	}
+	if s.Len() != n {
+		t.Fatalf("%v: Len() = %d, want %d", s, s.Len(), n)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		a, b := &Set{}, &Set{}
+		ma, mb := model{}, model{}
+		for _, sm := range []struct {
+			s *Set
+			m model
+		}{{a, ma}, {b, mb}} {
+			for n := rnd.Intn(6); n > 0; n-- {
+				iv := randomInterval(rnd)
+				add := rnd.Intn(4) > 0
+				if add {
+					sm.s.Add(iv)
+				} else {
+					sm.s.Remove(iv)
+				}
+				for x := iv.Lo; x <= iv.Hi; x++ {
+					sm.m[x] = add
+				}
+				check(t, sm.s, sm.m)
+			}
+		}
+
+		union, inter, diff := model{}, model{}, model{}
+		for x := lo - 15; x <= hi+15; x++ {
+			union[x] = ma[x] || mb[x]
+			inter[x] = ma[x] && mb[x]
+			diff[x] = ma[x] && !mb[x]
+		}
+		check(t, a.Union(b), union)
+		check(t, a.Intersect(b), inter)
+		check(t, a.Subtract(b), diff)
+		check(t, a, ma) // the operations leave their operands alone
+
+		within := randomInterval(rnd)
+		gaps := NewSet(a.Gaps(within)...)
+		for x := within.Lo; x <= within.Hi; x++ {
+			if gaps.Contains(x) == ma[x] {
+				t.Fatalf("%v.Gaps(%v) = %v, wrong at %d", a, within, gaps, x)
+			}
+		}
+		if !gaps.Intersect(NewSet(New(lo-15, within.Lo-1), New(within.Hi+1, hi+15))).Empty() {
+			t.Fatalf("%v.Gaps(%v) = %v reaches outside", a, within, gaps)
+		}
+		if a.Covers(within) != (within.Len() > 0 && len(a.Gaps(within)) == 0 || within.Empty()) {
+			t.Fatalf("%v.Covers(%v) = %v", a, within, a.Covers(within))
+		}
+	}
+}
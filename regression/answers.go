@@ -0,0 +1,64 @@
+// Package regression checks every registered solver against the accepted
+// answers of the real puzzle inputs recorded in answers.json.
+//
+// After a new day is solved, its answers are recorded with
+//
+//	go test ./regression -update
+package regression
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+
+	"aoc/solver"
+)
+
+// Record is the accepted answer of a single part.
+type Record struct {
+	Day    int           `json:"day"`
+	Part   int           `json:"part"`
+	Answer solver.Answer `json:"answer"`
+}
+
+// Answers maps a day and part to its accepted answer.
+type Answers map[[2]int]solver.Answer
+
+// Load reads the answers file at path.
+func Load(path string) (Answers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+
+	answers := make(Answers)
+	for _, r := range records {
+		answers[[2]int{r.Day, r.Part}] = r.Answer
+	}
+	return answers, nil
+}
+
+// Save writes the answers sorted by day and part to path.
+func (a Answers) Save(path string) error {
+	records := make([]Record, 0, len(a))
+	for key, answer := range a {
+		records = append(records, Record{key[0], key[1], answer})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Day != records[j].Day {
+			return records[i].Day < records[j].Day
+		}
+		return records[i].Part < records[j].Part
+	})
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
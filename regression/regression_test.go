@@ -0,0 +1,65 @@
+package regression
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "aoc/days"
+	"aoc/solver"
+)
+
+var update = flag.Bool("update", false, "record the current answers in answers.json")
+
+const answersPath = "../answers.json"
+
+func TestAnswers(t *testing.T) {
+	answers, err := Load(answersPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, day := range solver.Days() {
+		s, _ := solver.Lookup(day)
+		path := filepath.Join("..", fmt.Sprintf("day%02d", day), "input.txt")
+
+		for _, part := range []int{1, 2} {
+			day, part := day, part
+			key := [2]int{day, part}
+
+			t.Run(fmt.Sprintf("day%02d/part%d", day, part), func(t *testing.T) {
+				f, err := os.Open(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+
+				got, err := solver.Solve(s, part, f)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if *update {
+					answers[key] = got
+					return
+				}
+
+				want, ok := answers[key]
+				if !ok {
+					t.Fatalf("no accepted answer recorded, got %q", got)
+				}
+				if got != want {
+					t.Errorf("got %q, want %q", got, want)
+				}
+			})
+		}
+	}
+
+	if *update {
+		if err := answers.Save(answersPath); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
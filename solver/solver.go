@@ -0,0 +1,94 @@
+// Package solver defines the interface all puzzle solutions implement and
+// the registry the days register themselves into.
+package solver
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Answer is the result of a puzzle part as it is entered on the website.
+type Answer string
+
+// Int converts an integer result into an Answer.
+func Int(n int) Answer {
+	return Answer(strconv.Itoa(n))
+}
+
+func (a Answer) String() string {
+	return string(a)
+}
+
+// Solver solves both parts of a day's puzzle.
+type Solver interface {
+	Part1(r io.Reader) (Answer, error)
+	Part2(r io.Reader) (Answer, error)
+}
+
+// Configurable is implemented by solvers accepting additional command line
+// flags, e.g. puzzle parameters which differ between example and real input.
+type Configurable interface {
+	Solver
+	Flags(fs *flag.FlagSet)
+}
+
+// Solve runs the given part (1 or 2) of s.
+func Solve(s Solver, part int, r io.Reader) (Answer, error) {
+	switch part {
+	case 1:
+		return s.Part1(r)
+	case 2:
+		return s.Part2(r)
+	default:
+		return "", fmt.Errorf("invalid part %d", part)
+	}
+}
+
+// -------------------------------- Registry --------------------------------
+
+var (
+	mu      sync.RWMutex
+	solvers = make(map[int]Solver)
+)
+
+// Register makes the solver of the given day available to the runner. It is
+// meant to be called from the init function of the day's package and panics
+// if the day is registered twice.
+func Register(day int, s Solver) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if s == nil {
+		panic("solver: Register solver is nil")
+	}
+	if _, dup := solvers[day]; dup {
+		panic(fmt.Sprintf("solver: Register called twice for day %d", day))
+	}
+	solvers[day] = s
+}
+
+// Lookup returns the solver registered for day.
+func Lookup(day int) (Solver, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	s, ok := solvers[day]
+	return s, ok
+}
+
+// Days returns the sorted list of registered days.
+func Days() []int {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	days := make([]int, 0, len(solvers))
+	for day := range solvers {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days
+}
@@ -0,0 +1,42 @@
+// Package solvertest provides helpers for testing solvers against the
+// example inputs of the puzzle descriptions.
+package solvertest
+
+import (
+	"strings"
+	"testing"
+
+	"aoc/solver"
+)
+
+// Example is a puzzle input together with its expected answers. An empty
+// answer is not checked.
+type Example struct {
+	Name         string
+	Input        string
+	Part1, Part2 solver.Answer
+}
+
+// Run solves every example with s and reports wrong answers.
+func Run(t *testing.T, s solver.Solver, examples []Example) {
+	t.Helper()
+
+	for _, ex := range examples {
+		ex := ex
+		t.Run(ex.Name, func(t *testing.T) {
+			for part, want := range []solver.Answer{ex.Part1, ex.Part2} {
+				if want == "" {
+					continue
+				}
+				got, err := solver.Solve(s, part+1, strings.NewReader(ex.Input))
+				if err != nil {
+					t.Errorf("part %d: %v", part+1, err)
+					continue
+				}
+				if got != want {
+					t.Errorf("part %d = %q, want %q", part+1, got, want)
+				}
+			}
+		})
+	}
+}